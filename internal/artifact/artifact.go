@@ -2,14 +2,21 @@
 package artifact
 
 import (
+	"crypto/md5"  // #nosec
+	"crypto/sha1" // #nosec
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
 	"io"
 	"os"
 	"sync"
 
 	"github.com/apex/log"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
 )
 
 // Type defines the type of an artifact
@@ -36,6 +43,10 @@ const (
 	Checksum
 	// Signature is a signature file
 	Signature
+	// UploadableSourceArchive is the archive of the source code
+	UploadableSourceArchive
+	// ChecksumSidecar is the checksum file of a single artifact
+	ChecksumSidecar
 )
 
 func (t Type) String() string {
@@ -50,10 +61,12 @@ func (t Type) String() string {
 	case DockerImage:
 	case PublishableDockerImage:
 		return "Docker Image"
-	case Checksum:
+	case Checksum, ChecksumSidecar:
 		return "Checksum"
 	case Signature:
 		return "Signature"
+	case UploadableSourceArchive:
+		return "Source"
 	}
 	return "unknown"
 }
@@ -78,33 +91,57 @@ func (a Artifact) ExtraOr(key string, or interface{}) interface{} {
 	return a.Extra[key]
 }
 
-// Checksum calculates the SHA256 checksum of the artifact.
-func (a Artifact) Checksum() (string, error) {
-	log.Debugf("calculating sha256sum for %s", a.Path)
+// Checksum calculates the checksum of the artifact using the given
+// algorithm, one of sha1, sha256, sha512, md5, blake2b and crc32.
+func (a Artifact) Checksum(algorithm string) (string, error) {
+	log.Debugf("calculating %s checksum for %s", algorithm, a.Path)
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
 	file, err := os.Open(a.Path)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to checksum")
 	}
 	defer file.Close() // nolint: errcheck
-	var hash = sha256.New()
-	_, err = io.Copy(hash, file)
+	_, err = io.Copy(h, file)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to checksum")
 	}
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha1":
+		return sha1.New(), nil // #nosec
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "md5":
+		return md5.New(), nil // #nosec
+	case "blake2b":
+		return blake2b.New512(nil)
+	case "crc32":
+		return crc32.NewIEEE(), nil
+	}
+	return nil, fmt.Errorf("invalid checksum algorithm: %s", algorithm)
 }
 
 // Artifacts is a list of artifacts
 type Artifacts struct {
-	items []Artifact
-	lock  *sync.Mutex
+	items   []Artifact
+	lock    *sync.Mutex
+	digests *digests
 }
 
 // New return a new list of artifacts
 func New() Artifacts {
 	return Artifacts{
-		items: []Artifact{},
-		lock:  &sync.Mutex{},
+		items:   []Artifact{},
+		lock:    &sync.Mutex{},
+		digests: newDigests(),
 	}
 }
 
@@ -167,6 +204,37 @@ func ByType(t Type) Filter {
 	}
 }
 
+// ByIDs is a predefined filter that filters by the IDs of the builds that
+// created the artifacts. Artifacts created from other artifacts, like
+// archives, match if any of their builds match, and checksum sidecars match
+// if their artifact does. Checksums, source archives and their signatures
+// always match. No IDs means no filtering at all.
+func ByIDs(ids ...string) Filter {
+	return func(a Artifact) bool {
+		if len(ids) == 0 || a.Type == Checksum || a.Type == UploadableSourceArchive {
+			return true
+		}
+		if a.Type == ChecksumSidecar {
+			src, ok := a.Extra["Artifact"].(Artifact)
+			return ok && ByIDs(ids...)(src)
+		}
+		if a.Type == Signature && (a.ExtraOr("Checksum", false).(bool) || a.ExtraOr("Source", false).(bool)) {
+			return true
+		}
+		for _, id := range ids {
+			if a.ExtraOr("ID", "") == id {
+				return true
+			}
+		}
+		for _, build := range a.ExtraOr("Builds", []Artifact{}).([]Artifact) {
+			if ByIDs(ids...)(build) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // Or performs an OR between all given filters
 func Or(filters ...Filter) Filter {
 	return func(a Artifact) bool {
@@ -197,6 +265,7 @@ func And(filters ...Filter) Filter {
 // You can compose filters by using the And and Or filters.
 func (artifacts *Artifacts) Filter(filter Filter) Artifacts {
 	var result = New()
+	result.digests = artifacts.digests
 	for _, a := range artifacts.items {
 		if filter(a) {
 			result.items = append(result.items, a)
@@ -106,6 +106,90 @@ func TestFilter(t *testing.T) {
 	).List(), 2)
 }
 
+func TestByIDs(t *testing.T) {
+	var server = Artifact{
+		Name:  "server",
+		Type:  Binary,
+		Extra: map[string]interface{}{"ID": "server"},
+	}
+	var client = Artifact{
+		Name:  "client",
+		Type:  Binary,
+		Extra: map[string]interface{}{"ID": "client"},
+	}
+	var data = []Artifact{
+		server,
+		client,
+		{
+			Name:  "server.tar.gz",
+			Type:  UploadableArchive,
+			Extra: map[string]interface{}{"Builds": []Artifact{server}},
+		},
+		{
+			Name:  "all.tar.gz",
+			Type:  UploadableArchive,
+			Extra: map[string]interface{}{"Builds": []Artifact{server, client}},
+		},
+		{
+			Name: "checksums.txt",
+			Type: Checksum,
+		},
+		{
+			Name:  "checksums.txt.sig",
+			Type:  Signature,
+			Extra: map[string]interface{}{"Checksum": true},
+		},
+		{
+			Name:  "server.tar.gz.sig",
+			Type:  Signature,
+			Extra: map[string]interface{}{"Builds": []Artifact{server}},
+		},
+		{
+			Name: "source.tar.gz",
+			Type: UploadableSourceArchive,
+		},
+		{
+			Name:  "source.tar.gz.sig",
+			Type:  Signature,
+			Extra: map[string]interface{}{"Source": true},
+		},
+		{
+			Name: "server.tar.gz.sha256",
+			Type: ChecksumSidecar,
+			Extra: map[string]interface{}{"Artifact": Artifact{
+				Name:  "server.tar.gz",
+				Type:  UploadableArchive,
+				Extra: map[string]interface{}{"Builds": []Artifact{server}},
+			}},
+		},
+		{
+			Name: "source.tar.gz.sha256",
+			Type: ChecksumSidecar,
+			Extra: map[string]interface{}{"Artifact": Artifact{
+				Name: "source.tar.gz",
+				Type: UploadableSourceArchive,
+			}},
+		},
+	}
+	var artifacts = New()
+	for _, a := range data {
+		artifacts.Add(a)
+	}
+
+	var names = func(ids ...string) []string {
+		var result []string
+		for _, a := range artifacts.Filter(ByIDs(ids...)).List() {
+			result = append(result, a.Name)
+		}
+		return result
+	}
+	assert.Len(t, names(), len(data))
+	assert.Equal(t, []string{"server", "server.tar.gz", "all.tar.gz", "checksums.txt", "checksums.txt.sig", "server.tar.gz.sig", "source.tar.gz", "source.tar.gz.sig", "server.tar.gz.sha256", "source.tar.gz.sha256"}, names("server"))
+	assert.Equal(t, []string{"client", "all.tar.gz", "checksums.txt", "checksums.txt.sig", "source.tar.gz", "source.tar.gz.sig", "source.tar.gz.sha256"}, names("client"))
+	assert.Equal(t, []string{"server", "client", "server.tar.gz", "all.tar.gz", "checksums.txt", "checksums.txt.sig", "server.tar.gz.sig", "source.tar.gz", "source.tar.gz.sig", "server.tar.gz.sha256", "source.tar.gz.sha256"}, names("client", "server"))
+	assert.Equal(t, []string{"checksums.txt", "checksums.txt.sig", "source.tar.gz", "source.tar.gz.sig", "source.tar.gz.sha256"}, names("nope"))
+}
+
 func TestGroupByPlatform(t *testing.T) {
 	var data = []Artifact{
 		{
@@ -149,16 +233,36 @@ func TestChecksum(t *testing.T) {
 		Path: file,
 	}
 
-	sum, err := artifact.Checksum()
-	require.NoError(t, err)
-	require.Equal(t, "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269", sum)
+	for algorithm, result := range map[string]string{
+		"sha1":    "bfb7759a67daeb65410490b4d98bb9da7d1ea2ce",
+		"sha256":  "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269",
+		"sha512":  "f80eebd9aabb1a15fb869ed568d858a5c0dca3d5da07a410e1bd988763918d973e344814625f7c844695b2de36ffd27af290d0e34362c51dee5947d58d40527a",
+		"md5":     "80a751fde577028640c419000e33eba6",
+		"blake2b": "ca0dbbe27fca7e5d97b612a76b66d9d42fd67ece4265a50c09ccaefcdc03d9d5a87fa1fddc926ae10c6667342c69df5c33117cf636fca82ac1377c2b4e23e2bc",
+		"crc32":   "72d7748e",
+	} {
+		t.Run(algorithm, func(t *testing.T) {
+			sum, err := artifact.Checksum(algorithm)
+			require.NoError(t, err)
+			require.Equal(t, result, sum)
+		})
+	}
+}
+
+func TestChecksumInvalidAlgorithm(t *testing.T) {
+	var artifact = Artifact{
+		Path: "/tmp/adasdasdas/asdasd/asdas",
+	}
+	sum, err := artifact.Checksum("sha1ng")
+	require.EqualError(t, err, `invalid checksum algorithm: sha1ng`)
+	require.Empty(t, sum)
 }
 
 func TestChecksumFileDoesntExist(t *testing.T) {
 	var artifact = Artifact{
 		Path: "/tmp/adasdasdas/asdasd/asdas",
 	}
-	sum, err := artifact.Checksum()
+	sum, err := artifact.Checksum("sha256")
 	require.EqualError(t, err, `failed to checksum: open /tmp/adasdasdas/asdasd/asdas: no such file or directory`)
 	require.Empty(t, sum)
 }
@@ -0,0 +1,90 @@
+package artifact
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// digests caches the checksums and sizes of artifact files by path
+type digests struct {
+	lock  sync.Mutex
+	files map[string]*digest
+}
+
+// digest holds the checksums and size of a file, which are only valid while
+// its mtime and size don't change
+type digest struct {
+	lock  sync.Mutex
+	mtime time.Time
+	size  int64
+	sums  map[string]string
+}
+
+func newDigests() *digests {
+	return &digests{files: map[string]*digest{}}
+}
+
+func (d *digests) get(path string) *digest {
+	if d == nil {
+		// lists not created with New don't cache anything
+		return &digest{}
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var f, ok = d.files[path]
+	if !ok {
+		f = &digest{}
+		d.files[path] = f
+	}
+	return f
+}
+
+// refresh drops the cached checksums if the file changed since they were
+// calculated. It must be called holding the digest lock.
+func (d *digest) refresh(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if d.sums == nil || !info.ModTime().Equal(d.mtime) || info.Size() != d.size {
+		d.mtime = info.ModTime()
+		d.size = info.Size()
+		d.sums = map[string]string{}
+	}
+	return nil
+}
+
+// Checksum returns the checksum of the given artifact using the given
+// algorithm, like Artifact.Checksum. It is only calculated once per file
+// and algorithm, unless the file changes.
+func (artifacts Artifacts) Checksum(a Artifact, algorithm string) (string, error) {
+	var d = artifacts.digests.get(a.Path)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if err := d.refresh(a.Path); err != nil {
+		return "", errors.Wrap(err, "failed to checksum")
+	}
+	if sum, ok := d.sums[algorithm]; ok {
+		return sum, nil
+	}
+	sum, err := a.Checksum(algorithm)
+	if err != nil {
+		return "", err
+	}
+	d.sums[algorithm] = sum
+	return sum, nil
+}
+
+// Size returns the size of the file of the given artifact
+func (artifacts Artifacts) Size(a Artifact) (int64, error) {
+	var d = artifacts.digests.get(a.Path)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if err := d.refresh(a.Path); err != nil {
+		return 0, errors.Wrap(err, "failed to get size")
+	}
+	return d.size, nil
+}
@@ -0,0 +1,64 @@
+package artifact
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedChecksum(t *testing.T) {
+	folder, err := ioutil.TempDir("", "goreleasertest")
+	require.NoError(t, err)
+	var file = filepath.Join(folder, "subject")
+	require.NoError(t, ioutil.WriteFile(file, []byte("lorem ipsum"), 0644))
+	var mtime = time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(file, mtime, mtime))
+
+	var artifacts = New()
+	var a = Artifact{Path: file}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sum, err := artifacts.Checksum(a, "sha256")
+			require.NoError(t, err)
+			require.Equal(t, "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269", sum)
+		}()
+	}
+	wg.Wait()
+
+	// same size and mtime, so the cached checksum is used
+	require.NoError(t, ioutil.WriteFile(file, []byte("LOREM IPSUM"), 0644))
+	require.NoError(t, os.Chtimes(file, mtime, mtime))
+	sum, err := artifacts.Filter(ByType(Binary)).Checksum(a, "sha256")
+	require.NoError(t, err)
+	require.Equal(t, "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269", sum)
+	sum, err = artifacts.Checksum(a, "crc32")
+	require.NoError(t, err)
+	require.NotEqual(t, "72d7748e", sum)
+
+	// a new mtime invalidates it
+	require.NoError(t, os.Chtimes(file, mtime.Add(time.Minute), mtime.Add(time.Minute)))
+	sum, err = artifacts.Checksum(a, "sha256")
+	require.NoError(t, err)
+	require.NotEqual(t, "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269", sum)
+
+	size, err := artifacts.Size(a)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), size)
+}
+
+func TestCachedChecksumErrors(t *testing.T) {
+	var artifacts = New()
+	var a = Artifact{Path: "/tmp/adasdasdas/asdasd/asdas"}
+	_, err := artifacts.Checksum(a, "sha256")
+	require.EqualError(t, err, "failed to checksum: stat /tmp/adasdasdas/asdasd/asdas: no such file or directory")
+	_, err = artifacts.Size(a)
+	require.EqualError(t, err, "failed to get size: stat /tmp/adasdasdas/asdasd/asdas: no such file or directory")
+}
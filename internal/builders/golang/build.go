@@ -7,6 +7,8 @@ import (
 	"go/token"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/apex/log"
@@ -15,6 +17,7 @@ import (
 	api "github.com/goreleaser/goreleaser/pkg/build"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	zglob "github.com/mattn/go-zglob"
 	"github.com/pkg/errors"
 )
 
@@ -104,6 +107,7 @@ func (*Builder) Build(ctx *context.Context, build config.Build, options api.Opti
 		Extra: map[string]interface{}{
 			"Binary": build.Binary,
 			"Ext":    options.Ext,
+			"ID":     build.ID,
 		},
 	})
 	return nil
@@ -161,6 +165,89 @@ func (b buildTarget) Env() []string {
 	}
 }
 
+// FindMains walks the given folder and returns all folders containing a main
+// package, skipping hidden, vendor and testdata folders.
+func FindMains(root string) ([]string, error) {
+	var mains []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		var name = info.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+			name == "vendor" || name == "testdata") {
+			return filepath.SkipDir
+		}
+		if isMainPackage(path) {
+			mains = append(mains, path)
+		}
+		return nil
+	})
+	return mains, err
+}
+
+// MainPackages returns the folders of all main packages matching the given
+// pattern, which may be a glob, e.g. ./cmd/*, or a folder followed by /...,
+// e.g. ./cmd/..., to match all main packages inside it.
+func MainPackages(pattern string) ([]string, error) {
+	var matches []string
+	if strings.HasSuffix(pattern, "...") {
+		var root = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+		mains, err := FindMains(root)
+		if err != nil {
+			return nil, err
+		}
+		matches = mains
+	} else {
+		globbed, err := zglob.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "globbing failed for pattern %s", pattern)
+		}
+		for _, match := range globbed {
+			if isMainPackage(match) {
+				matches = append(matches, match)
+			}
+		}
+	}
+	var result = make([]string, 0, len(matches))
+	for _, match := range matches {
+		// go build treats folders not starting with . as import paths
+		if !filepath.IsAbs(match) && !strings.HasPrefix(match, ".") {
+			match = "./" + filepath.ToSlash(match)
+		}
+		result = append(result, match)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// IsMainPattern returns true if the given main is a pattern matching several
+// main packages.
+func IsMainPattern(main string) bool {
+	return strings.ContainsAny(main, "*?[") || strings.HasSuffix(main, "...")
+}
+
+func isMainPackage(dir string) bool {
+	stat, err := os.Stat(dir)
+	if err != nil || !stat.IsDir() {
+		return false
+	}
+	packs, err := parser.ParseDir(token.NewFileSet(), dir, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return false
+	}
+	if _, ok := packs["main"]; !ok {
+		return false
+	}
+	return checkMain(config.Build{Main: dir}) == nil
+}
+
 func checkMain(build config.Build) error {
 	var main = build.Main
 	if main == "" {
@@ -3,6 +3,7 @@ package golang
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -81,6 +82,7 @@ func TestBuild(t *testing.T) {
 	var config = config.Project{
 		Builds: []config.Build{
 			{
+				ID:     "foo",
 				Env:    []string{"GO111MODULE=off"},
 				Binary: "foo",
 				Targets: []string{
@@ -122,6 +124,7 @@ func TestBuild(t *testing.T) {
 			Extra: map[string]interface{}{
 				"Ext":    "",
 				"Binary": "foo",
+				"ID":     "foo",
 			},
 		},
 		{
@@ -133,6 +136,7 @@ func TestBuild(t *testing.T) {
 			Extra: map[string]interface{}{
 				"Ext":    "",
 				"Binary": "foo",
+				"ID":     "foo",
 			},
 		},
 		{
@@ -145,6 +149,7 @@ func TestBuild(t *testing.T) {
 			Extra: map[string]interface{}{
 				"Ext":    "",
 				"Binary": "foo",
+				"ID":     "foo",
 			},
 		},
 		{
@@ -156,6 +161,7 @@ func TestBuild(t *testing.T) {
 			Extra: map[string]interface{}{
 				"Ext":    ".exe",
 				"Binary": "foo",
+				"ID":     "foo",
 			},
 		},
 	})
@@ -386,6 +392,32 @@ func TestRunPipeWithMainFuncNotInMainGoFile(t *testing.T) {
 	})
 }
 
+func TestFindMains(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	for _, dir := range []string{
+		"cmd/foo", "cmd/bar", "cmd/nomain", "pkg/lib", "vendor/dep", "testdata/x", ".hidden",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(folder, dir), 0755))
+	}
+	writeGoodMain(t, folder)
+	writeGoodMain(t, filepath.Join(folder, "cmd/foo"))
+	writeGoodMain(t, filepath.Join(folder, "cmd/bar"))
+	writeGoodMain(t, filepath.Join(folder, "vendor/dep"))
+	writeGoodMain(t, filepath.Join(folder, "testdata/x"))
+	writeGoodMain(t, filepath.Join(folder, ".hidden"))
+	writeMainWithoutMainFunc(t, filepath.Join(folder, "cmd/nomain"))
+	assert.NoError(t, ioutil.WriteFile(
+		filepath.Join(folder, "pkg/lib/lib.go"),
+		[]byte("package lib\nfunc main() {}"),
+		0644,
+	))
+
+	mains, err := FindMains(".")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".", "cmd/bar", "cmd/foo"}, mains)
+}
+
 func TestLdFlagsFullTemplate(t *testing.T) {
 	var ctx = &context.Context{
 		Git: context.GitInfo{
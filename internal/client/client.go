@@ -3,8 +3,13 @@ package client
 
 import (
 	"bytes"
+	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
@@ -16,9 +21,102 @@ type Info struct {
 	URL         string
 }
 
+// PullRequest merged in the repository
+type PullRequest struct {
+	Number   int
+	Title    string
+	Author   string
+	Labels   []string
+	URL      string
+	MergedAt time.Time
+}
+
 // Client interface
 type Client interface {
 	CreateRelease(ctx *context.Context, body string) (releaseID int64, err error)
 	CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo config.Repo, content bytes.Buffer, path, message string) (err error)
 	Upload(ctx *context.Context, releaseID int64, name string, file *os.File) (err error)
+	MergedPullRequests(ctx *context.Context, repo config.Repo, from, to string) (prs []PullRequest, err error)
+}
+
+// New returns the client implementation of the provider the release is made
+// on
+func New(ctx *context.Context) (Client, error) {
+	if IsGitLab(ctx) {
+		return NewGitLab(ctx, ctx.Token)
+	}
+	return NewGitHub(ctx, ctx.Token)
+}
+
+// IsGitLab tells whether the release is made on GitLab instead of GitHub
+func IsGitLab(ctx *context.Context) bool {
+	return ctx.Config.Release.GitLab.Name != ""
+}
+
+// DefaultRepo sets the release repository from the given git remote URL,
+// unless one is configured, and the GitLab URLs if it is on GitLab
+func DefaultRepo(ctx *context.Context, remote string) {
+	if remote != "" && ctx.Config.Release.GitHub.Name == "" && ctx.Config.Release.GitLab.Name == "" {
+		setRepo(ctx, remote)
+	}
+	if IsGitLab(ctx) {
+		var urls = &ctx.Config.GitLabURLs
+		if urls.Download == "" {
+			urls.Download = "https://gitlab.com"
+		}
+		if urls.API == "" {
+			urls.API = strings.TrimSuffix(urls.Download, "/") + "/api/v4"
+		}
+	}
+}
+
+// setRepo sets the release repository from the remote URL, on GitLab if the
+// host looks like a GitLab instance and on GitHub otherwise
+func setRepo(ctx *context.Context, remote string) {
+	var repo = git.ExtractRepoFromURL(remote)
+	var host = git.ExtractHostFromURL(remote)
+	var gitlab = strings.Contains(host, "gitlab") || (host != "" &&
+		(host == urlHost(ctx.Config.GitLabURLs.Download) || host == urlHost(ctx.Config.GitLabURLs.API)))
+	if !gitlab {
+		ctx.Config.Release.GitHub = repo
+		return
+	}
+	ctx.Config.Release.GitLab = repo
+	if ctx.Config.GitLabURLs.Download == "" {
+		ctx.Config.GitLabURLs.Download = "https://" + host
+	}
+}
+
+func urlHost(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// ReleaseRepo returns the repository the release is made on
+func ReleaseRepo(ctx *context.Context) config.Repo {
+	if IsGitLab(ctx) {
+		return ctx.Config.Release.GitLab
+	}
+	return ctx.Config.Release.GitHub
+}
+
+// ReleaseURLTemplate returns the template of the download URL of the
+// artifacts uploaded to the release
+func ReleaseURLTemplate(ctx *context.Context) string {
+	if IsGitLab(ctx) {
+		return fmt.Sprintf(
+			"%s/%s/-/releases/{{ .Tag }}/downloads/{{ .ArtifactName }}",
+			strings.TrimSuffix(ctx.Config.GitLabURLs.Download, "/"),
+			ctx.Config.Release.GitLab.String(),
+		)
+	}
+	return fmt.Sprintf(
+		"%s/%s/%s/releases/download/{{ .Tag }}/{{ .ArtifactName }}",
+		ctx.Config.GitHubURLs.Download,
+		ctx.Config.Release.GitHub.Owner,
+		ctx.Config.Release.GitHub.Name,
+	)
 }
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func TestDefaultRepo(t *testing.T) {
+	for _, tt := range []struct {
+		remote   string
+		urls     config.GitLabURLs
+		expected config.GitLabURLs
+	}{
+		{
+			remote: "git@github.com:goreleaser/goreleaser.git",
+		},
+		{
+			remote:   "git@gitlab.com:goreleaser/goreleaser.git",
+			expected: config.GitLabURLs{Download: "https://gitlab.com", API: "https://gitlab.com/api/v4"},
+		},
+		{
+			remote:   "https://gitlab.company.com/goreleaser/goreleaser",
+			expected: config.GitLabURLs{Download: "https://gitlab.company.com", API: "https://gitlab.company.com/api/v4"},
+		},
+		{
+			remote:   "https://git.company.com/goreleaser/goreleaser",
+			urls:     config.GitLabURLs{API: "https://git.company.com/api/v4"},
+			expected: config.GitLabURLs{Download: "https://git.company.com", API: "https://git.company.com/api/v4"},
+		},
+	} {
+		t.Run(tt.remote, func(t *testing.T) {
+			var ctx = context.New(config.Project{GitLabURLs: tt.urls})
+			DefaultRepo(ctx, tt.remote)
+			assert.Equal(t, "goreleaser/goreleaser", ReleaseRepo(ctx).String())
+			assert.Equal(t, tt.expected.Download != "", IsGitLab(ctx))
+			assert.Equal(t, tt.expected, ctx.Config.GitLabURLs)
+		})
+	}
+}
+
+func TestDefaultRepoConfigured(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	ctx.Config.Release.GitLab = config.Repo{Owner: "foo", Name: "bar"}
+	DefaultRepo(ctx, "git@github.com:goreleaser/goreleaser.git")
+	assert.Equal(t, "foo/bar", ReleaseRepo(ctx).String())
+	assert.Equal(t, "https://gitlab.com", ctx.Config.GitLabURLs.Download)
+	assert.Equal(t, "https://gitlab.com/api/v4", ctx.Config.GitLabURLs.API)
+}
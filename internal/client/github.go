@@ -2,8 +2,13 @@ package client
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/google/go-github/github"
@@ -17,10 +22,10 @@ type githubClient struct {
 	client *github.Client
 }
 
-// NewGitHub returns a github client implementation
-func NewGitHub(ctx *context.Context) (Client, error) {
+// NewGitHub returns a github client implementation using the given token
+func NewGitHub(ctx *context.Context, token string) (Client, error) {
 	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: ctx.Token},
+		&oauth2.Token{AccessToken: token},
 	)
 	client := github.NewClient(oauth2.NewClient(ctx, ts))
 	if ctx.Config.GitHubURLs.API != "" {
@@ -146,3 +151,110 @@ func (c *githubClient) Upload(
 	)
 	return err
 }
+
+// MergedPullRequests lists the pull requests whose merge commit is between
+// the given refs, in the order they were merged
+func (c *githubClient) MergedPullRequests(
+	ctx *context.Context,
+	repo config.Repo,
+	from, to string,
+) ([]PullRequest, error) {
+	comparison, err := c.compareCommits(ctx, repo, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var commits = map[string]bool{}
+	var since time.Time
+	for _, commit := range comparison {
+		commits[commit.GetSHA()] = true
+		var date = commit.GetCommit().GetCommitter().GetDate()
+		if since.IsZero() || date.Before(since) {
+			since = date
+		}
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	// nolint: prealloc
+	var result []PullRequest
+	var opts = &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		prs, res, err := c.client.PullRequests.List(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		var done = res.NextPage == 0
+		for _, pr := range prs {
+			// pull requests are updated when merged, so the ones updated
+			// before the first commit can't have been merged after it
+			if pr.GetUpdatedAt().Before(since) {
+				done = true
+				break
+			}
+			if pr.MergedAt == nil || !commits[pr.GetMergeCommitSHA()] {
+				continue
+			}
+			var labels = []string{}
+			for _, label := range pr.Labels {
+				labels = append(labels, label.GetName())
+			}
+			result = append(result, PullRequest{
+				Number:   pr.GetNumber(),
+				Title:    pr.GetTitle(),
+				Author:   pr.GetUser().GetLogin(),
+				Labels:   labels,
+				URL:      pr.GetHTMLURL(),
+				MergedAt: pr.GetMergedAt(),
+			})
+		}
+		if done {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].MergedAt.Before(result[j].MergedAt)
+	})
+	return result, nil
+}
+
+// compareCommits lists all the commits between the given refs. The compare
+// API lists up to 250 commits unless paginated, which the go-github version
+// in use doesn't support, so the pages are requested here.
+func (c *githubClient) compareCommits(
+	ctx *context.Context,
+	repo config.Repo,
+	from, to string,
+) ([]github.RepositoryCommit, error) {
+	var result []github.RepositoryCommit
+	var query = url.Values{"per_page": {"100"}}
+	for {
+		var path = fmt.Sprintf("repos/%s/%s/compare/%s...%s?%s", repo.Owner, repo.Name, from, to, query.Encode())
+		req, err := c.client.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var comparison github.CommitsComparison
+		res, err := c.client.Do(ctx, req, &comparison)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, comparison.Commits...)
+		if res.NextPage == 0 {
+			if len(result) < comparison.GetTotalCommits() {
+				return nil, fmt.Errorf(
+					"github: comparing %s...%s only listed %d of %d commits",
+					from, to, len(result), comparison.GetTotalCommits(),
+				)
+			}
+			return result, nil
+		}
+		query.Set("page", strconv.Itoa(res.NextPage))
+	}
+}
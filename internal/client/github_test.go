@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func TestMergedPullRequests(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/foo/bar/compare/v1.0.0...v1.1.0":
+			fmt.Fprint(w, `{"commits": [
+				{"sha": "aaa", "commit": {"committer": {"date": "2019-03-01T10:00:00Z"}}},
+				{"sha": "bbb", "commit": {"committer": {"date": "2019-03-02T10:00:00Z"}}}
+			]}`)
+		case "/repos/foo/bar/pulls":
+			assert.Equal(t, "closed", r.URL.Query().Get("state"))
+			assert.Equal(t, "updated", r.URL.Query().Get("sort"))
+			assert.Equal(t, "desc", r.URL.Query().Get("direction"))
+			switch r.URL.Query().Get("page") {
+			case "":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/pulls?page=2>; rel="next"`, srv.URL))
+				fmt.Fprint(w, `[
+					{"number": 12, "title": "Open later", "updated_at": "2019-03-05T10:00:00Z"},
+					{"number": 11, "title": "Second", "html_url": "https://github.com/foo/bar/pull/11", "merge_commit_sha": "bbb", "merged_at": "2019-03-02T10:00:00Z", "updated_at": "2019-03-04T10:00:00Z", "user": {"login": "bob"}}
+				]`)
+			case "2":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/pulls?page=3>; rel="next"`, srv.URL))
+				fmt.Fprint(w, `[
+					{"number": 10, "title": "First", "html_url": "https://github.com/foo/bar/pull/10", "merge_commit_sha": "aaa", "merged_at": "2019-03-01T10:00:00Z", "updated_at": "2019-03-03T10:00:00Z", "user": {"login": "alice"}, "labels": [{"name": "bug"}, {"name": "help wanted"}]},
+					{"number": 9, "title": "Too old", "merge_commit_sha": "zzz", "merged_at": "2019-02-01T10:00:00Z", "updated_at": "2019-02-01T10:00:00Z"}
+				]`)
+			default:
+				t.Errorf("should have stopped at the pull requests updated before the first commit")
+			}
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{API: srv.URL + "/", Upload: srv.URL + "/"},
+	})
+	c, err := NewGitHub(ctx, ctx.Token)
+	require.NoError(t, err)
+	prs, err := c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+	require.Len(t, prs, 2)
+	assert.Equal(t, 10, prs[0].Number)
+	assert.Equal(t, "First", prs[0].Title)
+	assert.Equal(t, "alice", prs[0].Author)
+	assert.Equal(t, []string{"bug", "help wanted"}, prs[0].Labels)
+	assert.Equal(t, "https://github.com/foo/bar/pull/10", prs[0].URL)
+	assert.Equal(t, 11, prs[1].Number)
+	assert.Equal(t, []string{}, prs[1].Labels)
+}
+
+// commitsJSON returns the JSON list of the commits with the given SHAs
+func commitsJSON(shas ...string) string {
+	var commits []string
+	for _, sha := range shas {
+		commits = append(commits, fmt.Sprintf(`{"sha": %q, "commit": {"committer": {"date": "2019-03-01T10:00:00Z"}}}`, sha))
+	}
+	return "[" + strings.Join(commits, ",") + "]"
+}
+
+func TestMergedPullRequestsManyCommits(t *testing.T) {
+	var shas []string
+	for i := 0; i < 260; i++ {
+		shas = append(shas, fmt.Sprintf("%040d", i))
+	}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/foo/bar/compare/v1.0.0...v1.1.0":
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			var page = shas[:100]
+			switch r.URL.Query().Get("page") {
+			case "":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/compare/v1.0.0...v1.1.0?per_page=100&page=2>; rel="next"`, srv.URL))
+			case "2":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/compare/v1.0.0...v1.1.0?per_page=100&page=3>; rel="next"`, srv.URL))
+				page = shas[100:200]
+			default:
+				page = shas[200:]
+			}
+			fmt.Fprintf(w, `{"total_commits": 260, "commits": %s}`, commitsJSON(page...))
+		case "/repos/foo/bar/pulls":
+			fmt.Fprintf(w, `[
+				{"number": 2, "title": "Last", "merge_commit_sha": %q, "merged_at": "2019-03-03T10:00:00Z", "updated_at": "2019-03-03T10:00:00Z"},
+				{"number": 1, "title": "First", "merge_commit_sha": %q, "merged_at": "2019-03-02T10:00:00Z", "updated_at": "2019-03-02T10:00:00Z"}
+			]`, shas[255], shas[0])
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{API: srv.URL + "/", Upload: srv.URL + "/"},
+	})
+	c, err := NewGitHub(ctx, ctx.Token)
+	require.NoError(t, err)
+	prs, err := c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+	require.Len(t, prs, 2)
+	assert.Equal(t, 1, prs[0].Number)
+	assert.Equal(t, 2, prs[1].Number)
+}
+
+func TestMergedPullRequestsTruncatedCommits(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// servers that don't paginate the comparison list 250 commits at most
+		var shas []string
+		for i := 0; i < 250; i++ {
+			shas = append(shas, fmt.Sprintf("%040d", i))
+		}
+		fmt.Fprintf(w, `{"total_commits": 300, "commits": %s}`, commitsJSON(shas...))
+	}))
+	defer srv.Close()
+
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{API: srv.URL + "/", Upload: srv.URL + "/"},
+	})
+	c, err := NewGitHub(ctx, ctx.Token)
+	require.NoError(t, err)
+	_, err = c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.EqualError(t, err, "github: comparing v1.0.0...v1.1.0 only listed 250 of 300 commits")
+}
+
+func TestMergedPullRequestsError(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{API: srv.URL + "/", Upload: srv.URL + "/"},
+	})
+	c, err := NewGitHub(ctx, ctx.Token)
+	require.NoError(t, err)
+	_, err = c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.Error(t, err)
+}
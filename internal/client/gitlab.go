@@ -0,0 +1,410 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+type gitlabClient struct {
+	client   *http.Client
+	api      string
+	download string
+	token    string
+}
+
+// gitlabError is an error response of the GitLab API
+type gitlabError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e gitlabError) Error() string {
+	return fmt.Sprintf("gitlab: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func isNotFound(err error) bool {
+	gerr, ok := err.(gitlabError)
+	return ok && gerr.StatusCode == http.StatusNotFound
+}
+
+// NewGitLab returns a gitlab client implementation using the given token
+func NewGitLab(ctx *context.Context, token string) (Client, error) {
+	var download = strings.TrimSuffix(ctx.Config.GitLabURLs.Download, "/")
+	if download == "" {
+		download = "https://gitlab.com"
+	}
+	var api = strings.TrimSuffix(ctx.Config.GitLabURLs.API, "/")
+	if api == "" {
+		api = download + "/api/v4"
+	}
+	if _, err := url.Parse(api); err != nil {
+		return &gitlabClient{}, err
+	}
+	return &gitlabClient{
+		client:   &http.Client{},
+		api:      api,
+		download: download,
+		token:    token,
+	}, nil
+}
+
+func (c *gitlabClient) CreateFile(
+	ctx *context.Context,
+	commitAuthor config.CommitAuthor,
+	repo config.Repo,
+	content bytes.Buffer,
+	path string,
+	message string,
+) error {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := c.request(ctx, http.MethodGet, projectPath(repo), nil, &project); err != nil {
+		return err
+	}
+	var data = map[string]string{
+		"branch":         project.DefaultBranch,
+		"content":        content.String(),
+		"commit_message": message,
+		"author_name":    commitAuthor.Name,
+		"author_email":   commitAuthor.Email,
+	}
+	var filePath = projectPath(repo) + "/repository/files/" + escape(path)
+	_, err := c.request(ctx, http.MethodGet, filePath+"?ref="+url.QueryEscape(project.DefaultBranch), nil, nil)
+	if isNotFound(err) {
+		_, err = c.request(ctx, http.MethodPost, filePath, data, nil)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = c.request(ctx, http.MethodPut, filePath, data, nil)
+	return err
+}
+
+// CreateRelease creates or updates the release of the current tag. GitLab
+// identifies releases by their tag, so the returned ID is always zero.
+func (c *gitlabClient) CreateRelease(ctx *context.Context, body string) (int64, error) {
+	title, err := tmpl.New(ctx).Apply(ctx.Config.Release.NameTemplate)
+	if err != nil {
+		return 0, err
+	}
+	if ctx.Config.Release.Draft {
+		log.Warn("gitlab doesn't support draft releases, publishing it")
+	}
+
+	var repo = ctx.Config.Release.GitLab
+	var path = projectPath(repo) + "/releases/" + escape(ctx.Git.CurrentTag)
+	var release struct {
+		Links struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	_, err = c.request(ctx, http.MethodGet, path, nil, nil)
+	if isNotFound(err) {
+		_, err = c.request(ctx, http.MethodPost, projectPath(repo)+"/releases", map[string]string{
+			"name":        title,
+			"tag_name":    ctx.Git.CurrentTag,
+			"description": body,
+		}, &release)
+	} else if err == nil {
+		_, err = c.request(ctx, http.MethodPut, path, map[string]string{
+			"name":        title,
+			"description": body,
+		}, &release)
+	}
+	if err != nil {
+		return 0, err
+	}
+	log.WithField("url", release.Links.Self).Info("release updated")
+	return 0, nil
+}
+
+// Upload uploads the file either to the package registry or as a project
+// upload, and links it to the release of the current tag, replacing the
+// link with the same name, if any
+func (c *gitlabClient) Upload(
+	ctx *context.Context,
+	releaseID int64,
+	name string,
+	file *os.File,
+) error {
+	var repo = ctx.Config.Release.GitLab
+	var link string
+	var err error
+	if ctx.Config.GitLabURLs.UsePackageRegistry {
+		link, err = c.uploadPackage(ctx, repo, name, file)
+	} else {
+		link, err = c.uploadProjectFile(ctx, repo, name, file)
+	}
+	if err != nil {
+		return err
+	}
+
+	var path = projectPath(repo) + "/releases/" + escape(ctx.Git.CurrentTag) + "/assets/links"
+	var links []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if _, err := c.request(ctx, http.MethodGet, path, nil, &links); err != nil {
+		return err
+	}
+	for _, l := range links {
+		if l.Name != name {
+			continue
+		}
+		if _, err := c.request(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", path, l.ID), nil, nil); err != nil {
+			return err
+		}
+	}
+	_, err = c.request(ctx, http.MethodPost, path, map[string]string{
+		"name":     name,
+		"url":      link,
+		"filepath": "/" + name,
+	}, nil)
+	return err
+}
+
+// uploadPackage uploads the file to the generic package of the project,
+// returning its download URL
+func (c *gitlabClient) uploadPackage(ctx *context.Context, repo config.Repo, name string, file *os.File) (string, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	var link = c.api + projectPath(repo) + "/packages/generic/" +
+		escape(ctx.Config.ProjectName) + "/" + escape(ctx.Version) + "/" + escape(name)
+	// the caller owns the file, so it must not be closed with the request
+	req, err := http.NewRequest(http.MethodPut, link, ioutil.NopCloser(file))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = c.send(ctx, req, nil)
+	return link, err
+}
+
+// uploadProjectFile uploads the file to the project, returning its download
+// URL
+func (c *gitlabClient) uploadProjectFile(ctx *context.Context, repo config.Repo, name string, file *os.File) (string, error) {
+	var reader, writer = io.Pipe()
+	var form = multipart.NewWriter(writer)
+	go func() {
+		part, err := form.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		_ = writer.CloseWithError(err)
+	}()
+	req, err := http.NewRequest(http.MethodPost, c.api+projectPath(repo)+"/uploads", reader)
+	if err != nil {
+		_ = reader.Close()
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	var upload struct {
+		URL string `json:"url"`
+	}
+	if _, err := c.send(ctx, req, &upload); err != nil {
+		return "", err
+	}
+	// the upload URL is relative to the project
+	return c.download + "/" + repo.String() + upload.URL, nil
+}
+
+// gitlabCommit is a commit listed by the GitLab API
+type gitlabCommit struct {
+	ID            string    `json:"id"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// compareCommits lists the commits between the given refs page by page, as
+// the compare API doesn't paginate
+func (c *gitlabClient) compareCommits(
+	ctx *context.Context,
+	repo config.Repo,
+	from, to string,
+) ([]gitlabCommit, error) {
+	var result []gitlabCommit
+	var page = "1"
+	for {
+		var commits []gitlabCommit
+		var query = url.Values{
+			"ref_name": {from + ".." + to},
+			"per_page": {"100"},
+			"page":     {page},
+		}
+		header, err := c.request(ctx, http.MethodGet, projectPath(repo)+"/repository/commits?"+query.Encode(), nil, &commits)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, commits...)
+		if page = header.Get("X-Next-Page"); page != "" {
+			continue
+		}
+		// the total is left out of very large lists
+		if total, err := strconv.Atoi(header.Get("X-Total")); err == nil && len(result) < total {
+			return nil, fmt.Errorf(
+				"gitlab: comparing %s...%s only listed %d of %d commits",
+				from, to, len(result), total,
+			)
+		}
+		return result, nil
+	}
+}
+
+// MergedPullRequests lists the merge requests whose merge, squash or head
+// commit is between the given refs, in the order they were merged
+func (c *gitlabClient) MergedPullRequests(
+	ctx *context.Context,
+	repo config.Repo,
+	from, to string,
+) ([]PullRequest, error) {
+	comparison, err := c.compareCommits(ctx, repo, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var commits = map[string]bool{}
+	var since time.Time
+	for _, commit := range comparison {
+		commits[commit.ID] = true
+		if since.IsZero() || commit.CommittedDate.Before(since) {
+			since = commit.CommittedDate
+		}
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	// nolint: prealloc
+	var result []PullRequest
+	var page = "1"
+	for page != "" {
+		var mrs []struct {
+			IID    int    `json:"iid"`
+			Title  string `json:"title"`
+			Author struct {
+				Username string `json:"username"`
+			} `json:"author"`
+			Labels          []string   `json:"labels"`
+			WebURL          string     `json:"web_url"`
+			SHA             string     `json:"sha"`
+			MergeCommitSHA  string     `json:"merge_commit_sha"`
+			SquashCommitSHA string     `json:"squash_commit_sha"`
+			MergedAt        *time.Time `json:"merged_at"`
+			UpdatedAt       time.Time  `json:"updated_at"`
+		}
+		var query = url.Values{
+			"state":    {"merged"},
+			"order_by": {"updated_at"},
+			"sort":     {"desc"},
+			"per_page": {"100"},
+			"page":     {page},
+		}
+		header, err := c.request(ctx, http.MethodGet, projectPath(repo)+"/merge_requests?"+query.Encode(), nil, &mrs)
+		if err != nil {
+			return nil, err
+		}
+		page = header.Get("X-Next-Page")
+		for _, mr := range mrs {
+			// merge requests are updated when merged, so the ones updated
+			// before the first commit can't have been merged after it
+			if mr.UpdatedAt.Before(since) {
+				page = ""
+				break
+			}
+			if mr.MergedAt == nil ||
+				!(commits[mr.MergeCommitSHA] || commits[mr.SquashCommitSHA] || commits[mr.SHA]) {
+				continue
+			}
+			var labels = []string{}
+			labels = append(labels, mr.Labels...)
+			result = append(result, PullRequest{
+				Number:   mr.IID,
+				Title:    mr.Title,
+				Author:   mr.Author.Username,
+				Labels:   labels,
+				URL:      mr.WebURL,
+				MergedAt: *mr.MergedAt,
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].MergedAt.Before(result[j].MergedAt)
+	})
+	return result, nil
+}
+
+// request sends a request to the given path of the API, encoding in and
+// decoding the response into out as JSON when they are not nil
+func (c *gitlabClient) request(ctx *context.Context, method, path string, in, out interface{}) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		bts, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(bts)
+	}
+	req, err := http.NewRequest(method, c.api+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(ctx, req, out)
+}
+
+func (c *gitlabClient) send(ctx *context.Context, req *http.Request, out interface{}) (http.Header, error) {
+	req = req.WithContext(ctx)
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	log.WithField("method", req.Method).WithField("url", req.URL.String()).Debug("calling gitlab")
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close() // nolint: errcheck
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		bts, _ := ioutil.ReadAll(res.Body)
+		return res.Header, gitlabError{
+			StatusCode: res.StatusCode,
+			Message:    strings.TrimSpace(string(bts)),
+		}
+	}
+	if out == nil {
+		return res.Header, nil
+	}
+	return res.Header, json.NewDecoder(res.Body).Decode(out)
+}
+
+// projectPath returns the API path of the project, whose owner might be a
+// group with subgroups
+func projectPath(repo config.Repo) string {
+	return "/projects/" + escape(repo.String())
+}
+
+// escape escapes the string to be used as a single path segment
+func escape(s string) string {
+	return strings.Replace(url.PathEscape(s), "/", "%2F", -1)
+}
@@ -0,0 +1,417 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// fakeGitLab is a fake of the parts of the GitLab API used by the client
+type fakeGitLab struct {
+	*httptest.Server
+	t        *testing.T
+	lock     sync.Mutex
+	releases map[string]map[string]string
+	links    map[string]string
+	files    map[string]map[string]string
+	uploads  map[string]string
+	packages map[string]string
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	var fake = &fakeGitLab{
+		t:        t,
+		releases: map[string]map[string]string{},
+		links:    map[string]string{},
+		files:    map[string]map[string]string{},
+		uploads:  map[string]string{},
+		packages: map[string]string{},
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	return fake
+}
+
+func (f *fakeGitLab) serve(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"401 Unauthorized"}`)
+		return
+	}
+	var path = r.URL.EscapedPath()
+	const project = "/api/v4/projects/foo%2Fbar"
+	if !strings.HasPrefix(path, project) {
+		f.notFound(w, r)
+		return
+	}
+	path = strings.TrimPrefix(path, project)
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		fmt.Fprint(w, `{"id": 42, "default_branch": "main"}`)
+	case path == "/releases" && r.Method == http.MethodPost:
+		var data = f.decode(r)
+		f.releases[data["tag_name"]] = data
+		fmt.Fprintf(w, `{"tag_name": %q, "_links": {"self": "https://gitlab.com/foo/bar/-/releases/%s"}}`, data["tag_name"], data["tag_name"])
+	case strings.HasPrefix(path, "/releases/") && strings.HasSuffix(path, "/assets/links"):
+		var tag = strings.TrimSuffix(strings.TrimPrefix(path, "/releases/"), "/assets/links")
+		if f.releases[tag] == nil {
+			f.notFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			var links = []map[string]interface{}{}
+			for i, name := range f.linkNames() {
+				links = append(links, map[string]interface{}{"id": i + 1, "name": name})
+			}
+			f.encode(w, links)
+		case http.MethodPost:
+			var data = f.decode(r)
+			assert.Equal(f.t, "/"+data["name"], data["filepath"])
+			f.links[data["name"]] = data["url"]
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		default:
+			f.notFound(w, r)
+		}
+	case strings.Contains(path, "/assets/links/") && r.Method == http.MethodDelete:
+		var id int
+		_, err := fmt.Sscanf(path[strings.LastIndex(path, "/")+1:], "%d", &id)
+		require.NoError(f.t, err)
+		delete(f.links, f.linkNames()[id-1])
+		fmt.Fprint(w, `{}`)
+	case strings.HasPrefix(path, "/releases/"):
+		var tag = strings.TrimPrefix(path, "/releases/")
+		if f.releases[tag] == nil {
+			f.notFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPut {
+			var data = f.decode(r)
+			data["tag_name"] = tag
+			f.releases[tag] = data
+		}
+		fmt.Fprintf(w, `{"tag_name": %q, "_links": {"self": "https://gitlab.com/foo/bar/-/releases/%s"}}`, tag, tag)
+	case path == "/uploads" && r.Method == http.MethodPost:
+		file, header, err := r.FormFile("file")
+		require.NoError(f.t, err)
+		bts, err := ioutil.ReadAll(file)
+		require.NoError(f.t, err)
+		f.uploads[header.Filename] = string(bts)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"url": "/uploads/abc123/%s"}`, header.Filename)
+	case strings.HasPrefix(path, "/packages/generic/") && r.Method == http.MethodPut:
+		bts, err := ioutil.ReadAll(r.Body)
+		require.NoError(f.t, err)
+		f.packages[strings.TrimPrefix(path, "/packages/generic/")] = string(bts)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"message": "201 Created"}`)
+	case strings.HasPrefix(path, "/repository/files/"):
+		var name = strings.TrimPrefix(path, "/repository/files/")
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(f.t, "main", r.URL.Query().Get("ref"))
+			if f.files[name] == nil {
+				f.notFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		case http.MethodPost, http.MethodPut:
+			var data = f.decode(r)
+			var exists = f.files[name] != nil
+			if exists != (r.Method == http.MethodPut) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"message": "A file with this name doesn't exist"}`)
+				return
+			}
+			f.files[name] = data
+			fmt.Fprint(w, `{}`)
+		}
+	default:
+		f.notFound(w, r)
+	}
+}
+
+func (f *fakeGitLab) linkNames() []string {
+	var names = []string{}
+	for name := range f.links {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (f *fakeGitLab) notFound(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, `{"message":"404 Not Found"}`)
+}
+
+func (f *fakeGitLab) decode(r *http.Request) map[string]string {
+	var data = map[string]string{}
+	require.NoError(f.t, json.NewDecoder(r.Body).Decode(&data))
+	return data
+}
+
+func (f *fakeGitLab) encode(w http.ResponseWriter, v interface{}) {
+	require.NoError(f.t, json.NewEncoder(w).Encode(v))
+}
+
+func gitlabContext(url string) *context.Context {
+	var ctx = context.New(config.Project{
+		ProjectName: "bar",
+		Release: config.Release{
+			GitLab:       config.Repo{Owner: "foo", Name: "bar"},
+			NameTemplate: "{{ .Tag }}",
+		},
+		GitLabURLs: config.GitLabURLs{
+			API:      url + "/api/v4",
+			Download: url,
+		},
+	})
+	ctx.Token = "secret"
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Version = "1.0.0"
+	return ctx
+}
+
+func TestNew(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	c, err := New(ctx)
+	require.NoError(t, err)
+	assert.IsType(t, &githubClient{}, c)
+
+	ctx.Config.Release.GitLab = config.Repo{Owner: "foo", Name: "bar"}
+	c, err = New(ctx)
+	require.NoError(t, err)
+	assert.IsType(t, &gitlabClient{}, c)
+	assert.Equal(t, "https://gitlab.com/api/v4", c.(*gitlabClient).api)
+}
+
+func TestReleaseURLTemplate(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "foo", Name: "bar"},
+		},
+		GitHubURLs: config.GitHubURLs{Download: "https://github.com"},
+		GitLabURLs: config.GitLabURLs{Download: "https://gitlab.example.com/"},
+	})
+	assert.Equal(t, config.Repo{Owner: "foo", Name: "bar"}, ReleaseRepo(ctx))
+	assert.Equal(t, "https://github.com/foo/bar/releases/download/{{ .Tag }}/{{ .ArtifactName }}", ReleaseURLTemplate(ctx))
+
+	ctx.Config.Release.GitLab = config.Repo{Owner: "group/sub", Name: "baz"}
+	assert.Equal(t, config.Repo{Owner: "group/sub", Name: "baz"}, ReleaseRepo(ctx))
+	assert.Equal(t, "https://gitlab.example.com/group/sub/baz/-/releases/{{ .Tag }}/downloads/{{ .ArtifactName }}", ReleaseURLTemplate(ctx))
+}
+
+func TestGitLabCreateRelease(t *testing.T) {
+	var srv = newFakeGitLab(t)
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+
+	_, err = c.CreateRelease(ctx, "first body")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":        "v1.0.0",
+		"tag_name":    "v1.0.0",
+		"description": "first body",
+	}, srv.releases["v1.0.0"])
+
+	ctx.Config.Release.NameTemplate = "{{ .ProjectName }} {{ .Tag }}"
+	_, err = c.CreateRelease(ctx, "second body")
+	require.NoError(t, err)
+	assert.Len(t, srv.releases, 1)
+	assert.Equal(t, map[string]string{
+		"name":        "bar v1.0.0",
+		"tag_name":    "v1.0.0",
+		"description": "second body",
+	}, srv.releases["v1.0.0"])
+}
+
+func TestGitLabCreateReleaseError(t *testing.T) {
+	var srv = newFakeGitLab(t)
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	ctx.Token = "wrong"
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+	_, err = c.CreateRelease(ctx, "body")
+	assert.EqualError(t, err, `gitlab: 401 Unauthorized: {"message":"401 Unauthorized"}`)
+}
+
+func TestGitLabUpload(t *testing.T) {
+	var folder, err = ioutil.TempDir("", "gitlabupload")
+	require.NoError(t, err)
+	var path = filepath.Join(folder, "bar.tar.gz")
+	require.NoError(t, ioutil.WriteFile(path, []byte("fake targz"), 0644))
+
+	for name, tt := range map[string]struct {
+		registry bool
+		url      string
+	}{
+		"project upload": {
+			url: "/foo/bar/uploads/abc123/bar.tar.gz",
+		},
+		"package registry": {
+			registry: true,
+			url:      "/api/v4/projects/foo%2Fbar/packages/generic/bar/1.0.0/bar.tar.gz",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var srv = newFakeGitLab(t)
+			defer srv.Close()
+
+			var ctx = gitlabContext(srv.URL)
+			ctx.Config.GitLabURLs.UsePackageRegistry = tt.registry
+			c, err := NewGitLab(ctx, ctx.Token)
+			require.NoError(t, err)
+			_, err = c.CreateRelease(ctx, "body")
+			require.NoError(t, err)
+
+			// uploading again replaces the link
+			for i := 0; i < 2; i++ {
+				file, err := os.Open(path)
+				require.NoError(t, err)
+				require.NoError(t, c.Upload(ctx, 0, "bar.tar.gz", file))
+				require.NoError(t, file.Close())
+			}
+			assert.Equal(t, map[string]string{"bar.tar.gz": srv.URL + tt.url}, srv.links)
+			if tt.registry {
+				assert.Equal(t, map[string]string{"bar/1.0.0/bar.tar.gz": "fake targz"}, srv.packages)
+				assert.Empty(t, srv.uploads)
+			} else {
+				assert.Equal(t, map[string]string{"bar.tar.gz": "fake targz"}, srv.uploads)
+				assert.Empty(t, srv.packages)
+			}
+		})
+	}
+}
+
+func TestGitLabUploadWithoutRelease(t *testing.T) {
+	var srv = newFakeGitLab(t)
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+	file, err := ioutil.TempFile("", "gitlabupload")
+	require.NoError(t, err)
+	defer file.Close() // nolint: errcheck
+	err = c.Upload(ctx, 0, "bar.tar.gz", file)
+	assert.EqualError(t, err, `gitlab: 404 Not Found: {"message":"404 Not Found"}`)
+}
+
+func TestGitLabCreateFile(t *testing.T) {
+	var srv = newFakeGitLab(t)
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+
+	var author = config.CommitAuthor{Name: "goreleaserbot", Email: "bot@goreleaser"}
+	var repo = config.Repo{Owner: "foo", Name: "bar"}
+	require.NoError(t, c.CreateFile(ctx, author, repo, *bytes.NewBufferString("first"), "Formula/bar.rb", "create"))
+	require.NoError(t, c.CreateFile(ctx, author, repo, *bytes.NewBufferString("second"), "Formula/bar.rb", "update"))
+	assert.Equal(t, map[string]map[string]string{
+		"Formula%2Fbar.rb": {
+			"branch":         "main",
+			"content":        "second",
+			"commit_message": "update",
+			"author_name":    "goreleaserbot",
+			"author_email":   "bot@goreleaser",
+		},
+	}, srv.files)
+}
+
+func TestGitLabMergedPullRequests(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/foo%2Fbar/repository/commits":
+			assert.Equal(t, "v1.0.0..v1.1.0", r.URL.Query().Get("ref_name"))
+			w.Header().Set("X-Total", "3")
+			switch r.URL.Query().Get("page") {
+			case "1":
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[
+					{"id": "ccc", "committed_date": "2019-03-02T11:00:00Z"},
+					{"id": "bbb", "committed_date": "2019-03-02T10:00:00Z"}
+				]`)
+			default:
+				fmt.Fprint(w, `[{"id": "aaa", "committed_date": "2019-03-01T10:00:00Z"}]`)
+			}
+		case "/api/v4/projects/foo%2Fbar/merge_requests":
+			assert.Equal(t, "merged", r.URL.Query().Get("state"))
+			assert.Equal(t, "updated_at", r.URL.Query().Get("order_by"))
+			assert.Equal(t, "desc", r.URL.Query().Get("sort"))
+			switch r.URL.Query().Get("page") {
+			case "1":
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[
+					{"iid": 12, "title": "Squashed", "web_url": "https://gitlab.com/foo/bar/-/merge_requests/12", "squash_commit_sha": "ccc", "merged_at": "2019-03-02T11:00:00Z", "updated_at": "2019-03-05T10:00:00Z", "author": {"username": "carol"}, "labels": []},
+					{"iid": 11, "title": "Second", "web_url": "https://gitlab.com/foo/bar/-/merge_requests/11", "merge_commit_sha": "bbb", "merged_at": "2019-03-02T10:00:00Z", "updated_at": "2019-03-04T10:00:00Z", "author": {"username": "bob"}}
+				]`)
+			case "2":
+				w.Header().Set("X-Next-Page", "3")
+				fmt.Fprint(w, `[
+					{"iid": 10, "title": "First", "web_url": "https://gitlab.com/foo/bar/-/merge_requests/10", "sha": "aaa", "merged_at": "2019-03-01T10:00:00Z", "updated_at": "2019-03-03T10:00:00Z", "author": {"username": "alice"}, "labels": ["bug", "help wanted"]},
+					{"iid": 9, "title": "Too old", "merge_commit_sha": "zzz", "merged_at": "2019-02-01T10:00:00Z", "updated_at": "2019-02-01T10:00:00Z"}
+				]`)
+			default:
+				t.Errorf("should have stopped at the merge requests updated before the first commit")
+			}
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+	prs, err := c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+	require.Len(t, prs, 3)
+	assert.Equal(t, 10, prs[0].Number)
+	assert.Equal(t, "First", prs[0].Title)
+	assert.Equal(t, "alice", prs[0].Author)
+	assert.Equal(t, []string{"bug", "help wanted"}, prs[0].Labels)
+	assert.Equal(t, "https://gitlab.com/foo/bar/-/merge_requests/10", prs[0].URL)
+	assert.Equal(t, 11, prs[1].Number)
+	assert.Equal(t, []string{}, prs[1].Labels)
+	assert.Equal(t, 12, prs[2].Number)
+}
+
+func TestGitLabMergedPullRequestsTruncated(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/foo%2Fbar/repository/commits", r.URL.EscapedPath())
+		w.Header().Set("X-Total", "3")
+		fmt.Fprint(w, `[{"id": "aaa", "committed_date": "2019-03-01T10:00:00Z"}]`)
+	}))
+	defer srv.Close()
+
+	var ctx = gitlabContext(srv.URL)
+	c, err := NewGitLab(ctx, ctx.Token)
+	require.NoError(t, err)
+	_, err = c.MergedPullRequests(ctx, config.Repo{Owner: "foo", Name: "bar"}, "v1.0.0", "v1.1.0")
+	require.EqualError(t, err, "gitlab: comparing v1.0.0...v1.1.0 only listed 1 of 3 commits")
+}
@@ -3,29 +3,36 @@
 package deprecate
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/apex/log"
 	"github.com/apex/log/handlers/cli"
 	"github.com/fatih/color"
+	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
 const baseURL = "https://goreleaser.com/deprecations#"
 
-// Notice warns the user about the deprecation of the given property
-func Notice(property string) {
-	cli.Default.Padding += 3
-	defer func() {
-		cli.Default.Padding -= 3
-	}()
+// Notice warns the user about the deprecation of the given property.
+// If the context is in strict mode, it returns an error instead.
+func Notice(ctx *context.Context, property string) error {
 	// replaces . and _ with -
 	url := baseURL + strings.NewReplacer(
 		".", "-",
 		"_", "-",
 	).Replace(property)
+	if ctx.Strict {
+		return fmt.Errorf("`%s` is deprecated and strict mode is enabled, check %s for more info", property, url)
+	}
+	cli.Default.Padding += 3
+	defer func() {
+		cli.Default.Padding -= 3
+	}()
 	log.Warn(color.New(color.Bold, color.FgHiYellow).Sprintf(
 		"DEPRECATED: `%s` should not be used anymore, check %s for more info.",
 		property,
 		url,
 	))
+	return nil
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/apex/log"
 	"github.com/apex/log/handlers/cli"
 	"github.com/fatih/color"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,7 +23,7 @@ func TestNotice(t *testing.T) {
 	log.SetHandler(cli.New(f))
 
 	log.Info("first")
-	Notice("foo.bar.whatever")
+	require.NoError(t, Notice(context.New(config.Project{}), "foo.bar.whatever"))
 	log.Info("last")
 
 	require.NoError(t, f.Close())
@@ -39,3 +41,13 @@ func TestNotice(t *testing.T) {
 
 	require.Equal(t, string(gbts), string(bts))
 }
+
+func TestNoticeStrict(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	ctx.Strict = true
+	require.EqualError(
+		t,
+		Notice(ctx, "foo.bar_whatever"),
+		"`foo.bar_whatever` is deprecated and strict mode is enabled, check https://goreleaser.com/deprecations#foo-bar-whatever for more info",
+	)
+}
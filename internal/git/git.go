@@ -38,3 +38,22 @@ func Clean(output string, err error) (string, error) {
 	}
 	return output, err
 }
+
+// PreviousTag returns the tag before the given ref, or nothing if there is
+// none
+func PreviousTag(ref string) string {
+	previous, err := Clean(Run("describe", "--tags", "--abbrev=0", TagRef(ref)+"^"))
+	if err != nil {
+		return ""
+	}
+	return previous
+}
+
+// TagRef disambiguates the ref if it is a tag, as there might be a branch
+// with the same name
+func TagRef(ref string) string {
+	if _, err := Run("rev-parse", "--verify", "--quiet", "refs/tags/"+ref); err == nil {
+		return "tags/" + ref
+	}
+	return ref
+}
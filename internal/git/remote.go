@@ -0,0 +1,68 @@
+package git
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+)
+
+// RemoteRepo gets the repo name from the origin remote URL
+func RemoteRepo() (result config.Repo, err error) {
+	out, err := RemoteURL()
+	if err != nil {
+		return result, err
+	}
+	return ExtractRepoFromURL(out), nil
+}
+
+// RemoteURL gets the origin remote URL
+func RemoteURL() (string, error) {
+	if !IsRepo() {
+		return "", errors.New("current folder is not a git repository")
+	}
+	out, err := Run("config", "--get", "remote.origin.url")
+	if err != nil {
+		return "", errors.New("repository doesn't have an `origin` remote")
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ExtractRepoFromURL gets the repo name from a git remote URL
+func ExtractRepoFromURL(s string) config.Repo {
+	// removes the .git suffix and any new lines
+	s = strings.NewReplacer(
+		".git", "",
+		"\n", "",
+	).Replace(s)
+	// if the URL contains a :, indicating a SSH config,
+	// remove all chars until it, including itself
+	// on HTTP and HTTPS URLs it will remove the http(s): prefix,
+	// which is ok. On SSH URLs the whole user@server will be removed,
+	// which is required.
+	s = s[strings.LastIndex(s, ":")+1:]
+	// split by /, the last to parts should be the owner and name
+	ss := strings.Split(s, "/")
+	return config.Repo{
+		Owner: ss[len(ss)-2],
+		Name:  ss[len(ss)-1],
+	}
+}
+
+// ExtractHostFromURL gets the host name from a git remote URL
+func ExtractHostFromURL(s string) string {
+	s = strings.TrimSpace(s)
+	// removes the scheme and the user, if any
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	if i := strings.Index(s, "@"); i >= 0 {
+		s = s[i+1:]
+	}
+	// the host ends at the port or the path on URLs, and at the : on SSH
+	// configs
+	if i := strings.IndexAny(s, ":/"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
@@ -129,7 +129,10 @@ func Upload(ctx *context.Context, puts []config.Put, kind string, check Response
 		put := put
 		filters := []artifact.Filter{}
 		if put.Checksum {
-			filters = append(filters, artifact.ByType(artifact.Checksum))
+			filters = append(filters,
+				artifact.ByType(artifact.Checksum),
+				artifact.ByType(artifact.ChecksumSidecar),
+			)
 		}
 		if put.Signature {
 			filters = append(filters, artifact.ByType(artifact.Signature))
@@ -141,6 +144,7 @@ func Upload(ctx *context.Context, puts []config.Put, kind string, check Response
 		case ModeArchive:
 			filters = append(filters,
 				artifact.ByType(artifact.UploadableArchive),
+				artifact.ByType(artifact.UploadableSourceArchive),
 				artifact.ByType(artifact.LinuxPackage),
 			)
 		case ModeBinary:
@@ -153,7 +157,11 @@ func Upload(ctx *context.Context, puts []config.Put, kind string, check Response
 			}).Error(err.Error())
 			return err
 		}
-		if err := uploadWithFilter(ctx, &put, artifact.Or(filters...), kind, check); err != nil {
+		var filter = artifact.And(
+			artifact.Or(filters...),
+			artifact.ByIDs(put.IDs...),
+		)
+		if err := uploadWithFilter(ctx, &put, filter, kind, check); err != nil {
 			return err
 		}
 	}
@@ -207,7 +215,7 @@ func uploadAsset(ctx *context.Context, put *config.Put, artifact artifact.Artifa
 
 	var headers = map[string]string{}
 	if put.ChecksumHeader != "" {
-		sum, err := artifact.Checksum()
+		sum, err := ctx.Artifacts.Checksum(artifact, "sha256")
 		if err != nil {
 			return err
 		}
@@ -325,6 +333,17 @@ type targetData struct {
 	Arm  string
 }
 
+// archiveReplacements returns the replacements of the archive config that
+// produced the given artifact
+func archiveReplacements(ctx *context.Context, artifact artifact.Artifact) map[string]string {
+	for _, archive := range ctx.Config.Archives {
+		if archive.ID == artifact.ExtraOr("ID", "") {
+			return archive.Replacements
+		}
+	}
+	return nil
+}
+
 // resolveTargetTemplate returns the resolved target template with replaced variables
 // Those variables can be replaced by the given context, goos, goarch, goarm and more
 func resolveTargetTemplate(ctx *context.Context, put *config.Put, artifact artifact.Artifact) (string, error) {
@@ -335,9 +354,10 @@ func resolveTargetTemplate(ctx *context.Context, put *config.Put, artifact artif
 	}
 
 	if put.Mode == ModeBinary {
-		data.Os = replace(ctx.Config.Archive.Replacements, artifact.Goos)
-		data.Arch = replace(ctx.Config.Archive.Replacements, artifact.Goarch)
-		data.Arm = replace(ctx.Config.Archive.Replacements, artifact.Goarm)
+		var replacements = archiveReplacements(ctx, artifact)
+		data.Os = replace(replacements, artifact.Goos)
+		data.Arch = replace(replacements, artifact.Goarch)
+		data.Arm = replace(replacements, artifact.Goarm)
 	}
 
 	var out bytes.Buffer
@@ -0,0 +1,234 @@
+// Package migrate rewrites goreleaser configuration files, replacing
+// deprecated options with their newer counterparts.
+//
+// Files are edited at the YAML node level, so comments and the ordering of
+// keys are preserved.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Migration rewrites a single deprecated property. Apply returns true if
+// the given document was changed.
+type Migration struct {
+	Property string
+	Apply    func(doc *yaml.Node) bool
+}
+
+// Migrations is the list of known migrations, named after the deprecation
+// notices they resolve
+// nolint: gochecknoglobals
+var Migrations = []Migration{
+	{Property: "docker.image", Apply: dockerImage},
+	{Property: "docker.binary", Apply: dockerBinary},
+	{Property: "git.short_hash", Apply: gitShortHash},
+}
+
+// Migrate applies all known migrations to the given config file contents.
+// It returns the rewritten file and the list of migrated properties, which
+// is empty if nothing changed.
+func Migrate(bts []byte) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(bts, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return bts, nil, nil
+	}
+	var migrated []string
+	for _, m := range Migrations {
+		if m.Apply(doc.Content[0]) {
+			migrated = append(migrated, m.Property)
+		}
+	}
+	if len(migrated) == 0 {
+		return bts, nil, nil
+	}
+	var out bytes.Buffer
+	var enc = yaml.NewEncoder(&out)
+	enc.SetIndent(indentation(doc.Content[0]))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	return out.Bytes(), migrated, nil
+}
+
+// dockerImage merges docker.image and docker.tag_templates into
+// docker.image_templates.
+func dockerImage(doc *yaml.Node) bool {
+	var changed bool
+	for _, docker := range items(get(doc, "dockers")) {
+		var image = get(docker, "image")
+		if image == nil {
+			continue
+		}
+		var tags = []string{"{{ .Version }}"}
+		if tt := get(docker, "tag_templates"); tt != nil {
+			tags = values(tt)
+			remove(docker, "tag_templates")
+		}
+		var templates = get(docker, "image_templates")
+		if templates == nil {
+			templates = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			replace(docker, "image", "image_templates", templates)
+		} else {
+			remove(docker, "image")
+		}
+		for _, tag := range tags {
+			templates.Content = append(
+				templates.Content,
+				scalar(fmt.Sprintf("%s:%s", image.Value, tag)),
+			)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// dockerBinary moves docker.binary into docker.binaries.
+func dockerBinary(doc *yaml.Node) bool {
+	var changed bool
+	for _, docker := range items(get(doc, "dockers")) {
+		var binary = get(docker, "binary")
+		if binary == nil {
+			continue
+		}
+		var binaries = get(docker, "binaries")
+		if binaries == nil {
+			binaries = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			replace(docker, "binary", "binaries", binaries)
+		} else {
+			remove(docker, "binary")
+		}
+		binaries.Content = append(binaries.Content, binary)
+		changed = true
+	}
+	return changed
+}
+
+// nolint: gochecknoglobals
+var commitRe = regexp.MustCompile(`\.Commit\b`)
+
+// gitShortHash removes git.short_hash, replacing .Commit by .ShortCommit in
+// all templates if it was enabled.
+func gitShortHash(doc *yaml.Node) bool {
+	var git = get(doc, "git")
+	var short = get(git, "short_hash")
+	if short == nil {
+		return false
+	}
+	if short.Value == "true" {
+		walk(doc, func(n *yaml.Node) {
+			if n.Kind == yaml.ScalarNode && strings.Contains(n.Value, "{{") {
+				n.Value = commitRe.ReplaceAllString(n.Value, ".ShortCommit")
+			}
+		})
+	}
+	remove(git, "short_hash")
+	if len(git.Content) == 0 {
+		remove(doc, "git")
+	}
+	return true
+}
+
+// indentation returns the indentation of the given document, which is the
+// column offset between the first key and its nested block value, so the
+// file is not re-indented when encoded. It defaults to 2 spaces.
+func indentation(doc *yaml.Node) int {
+	var indent = 2
+	var found bool
+	walk(doc, func(n *yaml.Node) {
+		if found || n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			var key, value = n.Content[i], n.Content[i+1]
+			if value.Kind != yaml.MappingNode && value.Kind != yaml.SequenceNode {
+				continue
+			}
+			if value.Style&yaml.FlowStyle != 0 || value.Line <= key.Line || value.Column <= key.Column {
+				continue
+			}
+			indent = value.Column - key.Column
+			found = true
+			return
+		}
+	})
+	return indent
+}
+
+// get returns the value of the given key in a mapping node, or nil.
+func get(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// remove deletes the given key from a mapping node.
+func remove(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// replace swaps the given key and its value by a new key and value, keeping
+// its position and comments.
+func replace(node *yaml.Node, key, newKey string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i].Value = newKey
+			node.Content[i+1] = value
+			return
+		}
+	}
+}
+
+// items returns the items of a sequence node.
+func items(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// values returns the scalar values of a sequence node or the value of a
+// single scalar node.
+func values(node *yaml.Node) []string {
+	if node.Kind == yaml.ScalarNode {
+		return []string{node.Value}
+	}
+	var result []string
+	for _, item := range items(node) {
+		result = append(result, item.Value)
+	}
+	return result
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func walk(node *yaml.Node, fn func(n *yaml.Node)) {
+	fn(node)
+	for _, child := range node.Content {
+		walk(child, fn)
+	}
+}
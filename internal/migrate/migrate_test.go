@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update .golden files")
+
+func TestMigrate(t *testing.T) {
+	for name, migrations := range map[string][]string{
+		"deprecated":         {"docker.image", "docker.binary", "git.short_hash"},
+		"deprecated_4spaces": {"docker.image", "docker.binary", "git.short_hash"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bts, err := ioutil.ReadFile("testdata/" + name + ".yml")
+			require.NoError(t, err)
+
+			out, migrated, err := Migrate(bts)
+			require.NoError(t, err)
+			require.Equal(t, migrations, migrated)
+
+			var golden = "testdata/" + name + ".yml.golden"
+			if *update {
+				require.NoError(t, ioutil.WriteFile(golden, out, 0655))
+			}
+			gbts, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(gbts), string(out))
+
+			// migrating again should be a no-op
+			again, migrated, err := Migrate(out)
+			require.NoError(t, err)
+			require.Empty(t, migrated)
+			require.Equal(t, string(out), string(again))
+		})
+	}
+}
+
+func TestMigrateNothingToDo(t *testing.T) {
+	var in = []byte("# a comment\ndockers:\n- image_templates: [foo/bar]\n")
+	out, migrated, err := Migrate(in)
+	require.NoError(t, err)
+	require.Empty(t, migrated)
+	require.Equal(t, in, out)
+}
+
+func TestMigrateShortHashDisabled(t *testing.T) {
+	out, migrated, err := Migrate([]byte("git:\n  short_hash: false\nsnapshot:\n  name_template: '{{ .Commit }}'\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"git.short_hash"}, migrated)
+	require.Equal(t, "snapshot:\n  name_template: '{{ .Commit }}'\n", string(out))
+}
+
+func TestMigrateDefaultTagTemplate(t *testing.T) {
+	out, migrated, err := Migrate([]byte("dockers:\n- image: foo/bar\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"docker.image"}, migrated)
+	require.Equal(t, "dockers:\n  - image_templates:\n      - foo/bar:{{ .Version }}\n", string(out))
+}
+
+func TestMigrateEmpty(t *testing.T) {
+	out, migrated, err := Migrate([]byte(""))
+	require.NoError(t, err)
+	require.Empty(t, migrated)
+	require.Empty(t, out)
+}
+
+func TestMigrateInvalidYAML(t *testing.T) {
+	_, _, err := Migrate([]byte("foo: [bar"))
+	require.Error(t, err)
+}
@@ -0,0 +1,18 @@
+// Package paths provides helpers for the paths of the files added to
+// archives, packages and images
+package paths
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Relative cleans the given destination, returning false if it is absolute
+// or if it goes up out of its root
+func Relative(dst string) (string, bool) {
+	dst = filepath.Clean(dst)
+	if filepath.IsAbs(dst) || dst == ".." || strings.HasPrefix(dst, ".."+string(filepath.Separator)) {
+		return dst, false
+	}
+	return dst, true
+}
@@ -0,0 +1,26 @@
+package paths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelative(t *testing.T) {
+	for dst, expected := range map[string]string{
+		"foo":           "foo",
+		"foo/":          "foo",
+		"./foo/../bar":  "bar",
+		"foo/../../bar": "",
+		"..":            "",
+		"../foo":        "",
+		"/etc/foo":      "",
+		"..foo":         "..foo",
+	} {
+		clean, ok := Relative(dst)
+		assert.Equal(t, expected != "", ok, dst)
+		if ok {
+			assert.Equal(t, expected, clean, dst)
+		}
+	}
+}
@@ -4,11 +4,17 @@
 package archive
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/campoy/unique"
@@ -16,6 +22,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/paths"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/archive"
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -27,6 +34,10 @@ const (
 	defaultBinaryNameTemplate = "{{ .Binary }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}{{ if .Arm }}v{{ .Arm }}{{ end }}"
 )
 
+// ErrArchiveAndArchives happens when both the deprecated archive and the
+// archives sections are set, as the archive section would be ignored
+var ErrArchiveAndArchives = errors.New("archive and archives can't be used together, move archive into archives")
+
 // nolint: gochecknoglobals
 var lock sync.Mutex
 
@@ -39,26 +50,47 @@ func (Pipe) String() string {
 
 // Default sets the pipe defaults
 func (Pipe) Default(ctx *context.Context) error {
-	var archive = &ctx.Config.Archive
-	if archive.Format == "" {
-		archive.Format = "tar.gz"
+	if len(ctx.Config.Archives) > 0 && !reflect.DeepEqual(ctx.Config.Archive, config.Archive{}) {
+		return ErrArchiveAndArchives
 	}
-	if len(archive.Files) == 0 {
-		archive.Files = []string{
-			"licence*",
-			"LICENCE*",
-			"license*",
-			"LICENSE*",
-			"readme*",
-			"README*",
-			"changelog*",
-			"CHANGELOG*",
-		}
+	if len(ctx.Config.Archives) == 0 {
+		ctx.Config.Archives = append(ctx.Config.Archives, ctx.Config.Archive)
 	}
-	if archive.NameTemplate == "" {
-		archive.NameTemplate = defaultNameTemplate
-		if archive.Format == "binary" {
-			archive.NameTemplate = defaultBinaryNameTemplate
+	var ids = map[string]bool{}
+	for i := range ctx.Config.Archives {
+		var archive = &ctx.Config.Archives[i]
+		if archive.ID == "" {
+			archive.ID = "default"
+		}
+		if ids[archive.ID] {
+			return fmt.Errorf("found 2 archives with the ID '%s', please fix your config", archive.ID)
+		}
+		ids[archive.ID] = true
+		if archive.Format == "" {
+			archive.Format = "tar.gz"
+		}
+		if usesFormat(*archive, "gz") {
+			if err := checkGz(ctx, *archive); err != nil {
+				return err
+			}
+		}
+		if len(archive.Files) == 0 {
+			archive.Files = []config.File{
+				{Source: "licence*"},
+				{Source: "LICENCE*"},
+				{Source: "license*"},
+				{Source: "LICENSE*"},
+				{Source: "readme*"},
+				{Source: "README*"},
+				{Source: "changelog*"},
+				{Source: "CHANGELOG*"},
+			}
+		}
+		if archive.NameTemplate == "" {
+			archive.NameTemplate = defaultNameTemplate
+			if archive.Format == "binary" {
+				archive.NameTemplate = defaultBinaryNameTemplate
+			}
 		}
 	}
 	return nil
@@ -66,26 +98,42 @@ func (Pipe) Default(ctx *context.Context) error {
 
 // Run the pipe
 func (Pipe) Run(ctx *context.Context) error {
-	var g errgroup.Group // TODO: use semerrgroup here
-	var filtered = ctx.Artifacts.Filter(artifact.ByType(artifact.Binary))
-	for group, artifacts := range filtered.GroupByPlatform() {
-		log.Debugf("group %s has %d binaries", group, len(artifacts))
-		artifacts := artifacts
-		g.Go(func() error {
-			if packageFormat(ctx, artifacts[0].Goos) == "binary" {
-				return skip(ctx, artifacts)
+	var groups = make([]map[string][]artifact.Artifact, len(ctx.Config.Archives))
+	for i, archive := range ctx.Config.Archives {
+		groups[i] = ctx.Artifacts.Filter(artifact.And(
+			artifact.ByType(artifact.Binary),
+			artifact.ByIDs(archive.IDs...),
+		)).GroupByPlatform()
+		// fail before creating any archive, as builds with a main pattern
+		// are only expanded into their binaries after the defaults are set
+		for group, artifacts := range groups[i] {
+			if packageFormat(archive, artifacts[0].Goos) == "gz" && len(artifacts) > 1 {
+				return fmt.Errorf("archive %s: gz archives can only hold a single binary, but %s has %d, use ids to select one build", archive.ID, group, len(artifacts))
 			}
-			return create(ctx, artifacts)
-		})
+		}
+	}
+	var g errgroup.Group // TODO: use semerrgroup here
+	for i, archive := range ctx.Config.Archives {
+		archive := archive
+		for group, artifacts := range groups[i] {
+			log.Debugf("group %s has %d binaries", group, len(artifacts))
+			artifacts := artifacts
+			g.Go(func() error {
+				if packageFormat(archive, artifacts[0].Goos) == "binary" {
+					return skip(ctx, archive, artifacts)
+				}
+				return create(ctx, archive, artifacts)
+			})
+		}
 	}
 	return g.Wait()
 }
 
-func create(ctx *context.Context, binaries []artifact.Artifact) error {
-	var format = packageFormat(ctx, binaries[0].Goos)
+func create(ctx *context.Context, arch config.Archive, binaries []artifact.Artifact) error {
+	format, level := formatAndLevel(arch, binaries[0].Goos)
 	folder, err := tmpl.New(ctx).
-		WithArtifact(binaries[0], ctx.Config.Archive.Replacements).
-		Apply(ctx.Config.Archive.NameTemplate)
+		WithArtifact(binaries[0], arch.Replacements).
+		Apply(arch.NameTemplate)
 	if err != nil {
 		return err
 	}
@@ -107,27 +155,46 @@ func create(ctx *context.Context, binaries []artifact.Artifact) error {
 	log.Info("creating")
 
 	wrap, err := tmpl.New(ctx).
-		WithArtifact(binaries[0], ctx.Config.Archive.Replacements).
-		Apply(wrapFolder(ctx.Config.Archive))
+		WithArtifact(binaries[0], arch.Replacements).
+		Apply(wrapFolder(arch))
 	if err != nil {
 		return err
 	}
 
-	var a = NewEnhancedArchive(archive.New(archiveFile), wrap)
-	defer a.Close() // nolint: errcheck
+	var files []entry
+	if format == "gz" {
+		// gz holds a single binary, without extra files or folders
+		wrap = ""
+	} else {
+		if files, err = findFiles(arch, binaries[0].Goos, binaries[0].Goarch); err != nil {
+			return fmt.Errorf("failed to find files to archive: %s", err.Error())
+		}
+		templated, err := templateFiles(ctx, arch, binaries[0])
+		if err != nil {
+			return err
+		}
+		files = append(files, templated...)
+	}
 
-	files, err := findFiles(ctx)
+	af, err := newArchive(ctx, arch, archiveFile, format, level)
 	if err != nil {
-		return fmt.Errorf("failed to find files to archive: %s", err.Error())
-	}
-	for _, f := range files {
-		if err = a.Add(f, f); err != nil {
-			return fmt.Errorf("failed to add %s to the archive: %s", f, err.Error())
-		}
+		return err
 	}
+	var a = NewEnhancedArchive(af, wrap)
+	defer a.Close() // nolint: errcheck
+
+	var entries = files
 	for _, binary := range binaries {
-		if err := a.Add(binary.Name, binary.Path); err != nil {
-			return fmt.Errorf("failed to add %s -> %s to the archive: %s", binary.Path, binary.Name, err.Error())
+		entries = append(entries, entry{name: binary.Name, path: binary.Path})
+	}
+	if arch.Reproducible {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].name < entries[j].name
+		})
+	}
+	for _, e := range entries {
+		if err := e.addTo(a); err != nil {
+			return fmt.Errorf("failed to add %s -> %s to the archive: %s", e.path, e.name, err.Error())
 		}
 	}
 	ctx.Artifacts.Add(artifact.Artifact{
@@ -139,11 +206,60 @@ func create(ctx *context.Context, binaries []artifact.Artifact) error {
 		Goarm:  binaries[0].Goarm,
 		Extra: map[string]interface{}{
 			"Builds": binaries,
+			"ID":     arch.ID,
+			"Format": format,
 		},
 	})
 	return nil
 }
 
+type entry struct {
+	name, path string
+	mode       os.FileMode
+	mtime      time.Time
+	// content of generated entries, which have no file on disk
+	content []byte
+}
+
+func (e entry) addTo(a archive.Archive) error {
+	if e.content != nil {
+		return archive.AddBytes(a, e.name, e.content, e.mode, e.mtime)
+	}
+	return archive.AddFile(a, e.name, e.path, e.mode, e.mtime)
+}
+
+// newArchive creates the archive, normalizing the metadata of its entries
+// if the archive should be reproducible
+func newArchive(ctx *context.Context, arch config.Archive, w io.Writer, format string, level int) (archive.Archive, error) {
+	if !arch.Reproducible {
+		return archive.NewFormat(w, format, level)
+	}
+	mtime, err := sourceDate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return archive.NewReproducible(w, format, level, mtime)
+}
+
+// sourceDate returns the time reproducible archives use as the mtime of all
+// their entries: SOURCE_DATE_EPOCH if set, or the date of the commit being
+// released otherwise
+func sourceDate(ctx *context.Context) (time.Time, error) {
+	var epoch = ctx.Env["SOURCE_DATE_EPOCH"]
+	if epoch == "" && ctx.Git.CommitDate.IsZero() {
+		log.Warn("commit date is unknown, archives won't be reproducible")
+		return time.Now().UTC(), nil
+	}
+	if epoch == "" {
+		return ctx.Git.CommitDate, nil
+	}
+	i, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %s", epoch)
+	}
+	return time.Unix(i, 0).UTC(), nil
+}
+
 func wrapFolder(a config.Archive) string {
 	switch a.WrapInDirectory {
 	case "true":
@@ -155,12 +271,12 @@ func wrapFolder(a config.Archive) string {
 	}
 }
 
-func skip(ctx *context.Context, binaries []artifact.Artifact) error {
+func skip(ctx *context.Context, arch config.Archive, binaries []artifact.Artifact) error {
 	for _, binary := range binaries {
 		log.WithField("binary", binary.Name).Info("skip archiving")
 		name, err := tmpl.New(ctx).
-			WithArtifact(binary, ctx.Config.Archive.Replacements).
-			Apply(ctx.Config.Archive.NameTemplate)
+			WithArtifact(binary, arch.Replacements).
+			Apply(arch.NameTemplate)
 		if err != nil {
 			return err
 		}
@@ -173,34 +289,181 @@ func skip(ctx *context.Context, binaries []artifact.Artifact) error {
 			Goarm:  binary.Goarm,
 			Extra: map[string]interface{}{
 				"Builds": []artifact.Artifact{binary},
+				"ID":     arch.ID,
+				"Format": "binary",
 			},
 		})
 	}
 	return nil
 }
 
-func findFiles(ctx *context.Context) (result []string, err error) {
-	for _, glob := range ctx.Config.Archive.Files {
-		files, err := zglob.Glob(glob)
+// findFiles returns the entries of the extra files of the archive for the
+// given platform
+func findFiles(arch config.Archive, goos, goarch string) (result []entry, err error) {
+	for _, f := range arch.Files {
+		if (f.Goos != "" && f.Goos != goos) || (f.Goarch != "" && f.Goarch != goarch) {
+			continue
+		}
+		if _, ok := paths.Relative(f.Destination); f.Destination != "" && !ok {
+			return result, fmt.Errorf("failed to add %s: dst %s is outside of the archive", f.Source, f.Destination)
+		}
+		var mtime time.Time
+		if f.MTime != "" {
+			mtime, err = time.Parse(time.RFC3339, f.MTime)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse mtime of %s: %s", f.Source, err.Error())
+			}
+		}
+		files, err := zglob.Glob(f.Source)
 		if err != nil {
-			return result, fmt.Errorf("globbing failed for pattern %s: %s", glob, err.Error())
+			return result, fmt.Errorf("globbing failed for pattern %s: %s", f.Source, err.Error())
+		}
+		for _, path := range files {
+			result = append(result, entry{
+				name:  destination(f, path),
+				path:  path,
+				mode:  f.Mode,
+				mtime: mtime,
+			})
 		}
-		result = append(result, files...)
 	}
 	// remove duplicates
 	unique.Slice(&result, func(i, j int) bool {
-		return strings.Compare(result[i], result[j]) < 0
+		if result[i].name != result[j].name {
+			return result[i].name < result[j].name
+		}
+		return result[i].path < result[j].path
 	})
 	return
 }
 
-func packageFormat(ctx *context.Context, platform string) string {
-	for _, override := range ctx.Config.Archive.FormatOverrides {
+// destination returns the name of the given file in the archive. The dst of
+// a glob is the folder its matches go into, while the dst of a single file
+// is its new name, unless it ends with a slash.
+func destination(f config.File, path string) string {
+	var name = path
+	if f.StripParent {
+		name = filepath.Base(path)
+	}
+	if f.Destination == "" {
+		return name
+	}
+	if isGlob(f.Source) || strings.HasSuffix(f.Destination, "/") {
+		return filepath.Join(f.Destination, name)
+	}
+	return f.Destination
+}
+
+func isGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[{")
+}
+
+// templateFiles renders the templated files of the archive, returning their
+// entries
+func templateFiles(ctx *context.Context, arch config.Archive, binary artifact.Artifact) ([]entry, error) {
+	var result = make([]entry, 0, len(arch.TemplatedFiles))
+	for _, f := range arch.TemplatedFiles {
+		var dst = f.Destination
+		if dst == "" {
+			dst = f.Source
+		}
+		dst, ok := paths.Relative(dst)
+		if !ok {
+			return nil, fmt.Errorf("failed to template %s: dst %s is outside of the archive", f.Source, dst)
+		}
+		content, err := tmpl.New(ctx).
+			WithArtifact(binary, arch.Replacements).
+			ApplyFile(f.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template %s: %s", f.Source, err.Error())
+		}
+		// the rendered file is as recent as its template
+		info, err := os.Stat(f.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template %s: %s", f.Source, err.Error())
+		}
+		var mode = f.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		result = append(result, entry{
+			name:    dst,
+			path:    f.Source,
+			mode:    mode,
+			mtime:   info.ModTime(),
+			content: []byte(content),
+		})
+	}
+	return result, nil
+}
+
+// usesFormat returns true if the archive is created in the given format for
+// any platform
+func usesFormat(arch config.Archive, format string) bool {
+	if arch.Format == format {
+		return true
+	}
+	for _, override := range arch.FormatOverrides {
+		if override.Format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGz rejects gz archives selecting more than one build, as gz can only
+// hold a single binary, and warns about the files they would ignore
+func checkGz(ctx *context.Context, arch config.Archive) error {
+	if len(arch.Files) > 0 || len(arch.TemplatedFiles) > 0 {
+		log.WithField("id", arch.ID).Warn("gz archives only hold the binary, files and templated_files will be ignored")
+	}
+	var builds = ctx.Config.Builds
+	if len(builds) == 0 {
+		builds = []config.Build{ctx.Config.SingleBuild}
+	}
+	var selected int
+	for _, build := range builds {
+		// builds are defaulted after archives, so their ids might be unset
+		var id = build.ID
+		if id == "" {
+			id = build.Binary
+		}
+		if id == "" {
+			id = ctx.Config.ProjectName
+		}
+		if len(arch.IDs) == 0 || contains(arch.IDs, id) {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return fmt.Errorf("archive %s: gz archives can only hold a single binary, but %d builds are selected, use ids to select one", arch.ID, selected)
+	}
+	return nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, s := range ids {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+func packageFormat(arch config.Archive, platform string) string {
+	format, _ := formatAndLevel(arch, platform)
+	return format
+}
+
+// formatAndLevel returns the archive format and compression level to use
+// for the given platform
+func formatAndLevel(arch config.Archive, platform string) (string, int) {
+	for _, override := range arch.FormatOverrides {
 		if strings.HasPrefix(platform, override.Goos) {
-			return override.Format
+			return override.Format, override.CompressionLevel
 		}
 	}
-	return ctx.Config.Archive.Format
+	return arch.Format, arch.CompressionLevel
 }
 
 // NewEnhancedArchive enhances a pre-existing archive.Archive instance
@@ -213,8 +476,8 @@ func NewEnhancedArchive(a archive.Archive, wrap string) archive.Archive {
 	}
 }
 
-// EnhancedArchive is an archive.Archive implementation which decorates an
-// archive.Archive adding wrap directory support, logging and windows
+// EnhancedArchive is an archive.EntryArchive implementation which decorates
+// an archive.Archive adding wrap directory support, logging and windows
 // backslash fixes.
 type EnhancedArchive struct {
 	a     archive.Archive
@@ -224,13 +487,29 @@ type EnhancedArchive struct {
 
 // Add adds a file
 func (d EnhancedArchive) Add(name, path string) error {
+	return d.AddFile(name, path, 0, time.Time{})
+}
+
+// AddFile adds a file overriding its mode and mtime
+func (d EnhancedArchive) AddFile(name, path string, mode os.FileMode, mtime time.Time) error {
 	name = strings.Replace(filepath.Join(d.wrap, name), "\\", "/", -1)
 	log.Debugf("adding file: %s as %s", path, name)
 	if _, ok := d.files[name]; ok {
 		return fmt.Errorf("file %s already exists in the archive", name)
 	}
 	d.files[name] = path
-	return d.a.Add(name, path)
+	return archive.AddFile(d.a, name, path, mode, mtime)
+}
+
+// AddEntry adds an entry with the given header info
+func (d EnhancedArchive) AddEntry(name string, info os.FileInfo, link string, r io.Reader) error {
+	name = strings.Replace(filepath.Join(d.wrap, name), "\\", "/", -1)
+	log.Debugf("adding entry: %s", name)
+	if _, ok := d.files[name]; ok {
+		return fmt.Errorf("file %s already exists in the archive", name)
+	}
+	d.files[name] = name
+	return archive.AddEntry(d.a, name, info, link, r)
 }
 
 // Close closes the underlying archive
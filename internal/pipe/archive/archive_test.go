@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
@@ -25,7 +26,7 @@ func TestDescription(t *testing.T) {
 func TestRunPipe(t *testing.T) {
 	folder, back := testlib.Mktmp(t)
 	defer back()
-	for _, format := range []string{"tar.gz", "zip"} {
+	for _, format := range []string{"tar.gz", "tar.xz", "tar.zst", "tar", "zip"} {
 		t.Run("Archive format "+format, func(tt *testing.T) {
 			var dist = filepath.Join(folder, format+"_dist")
 			require.NoError(t, os.Mkdir(dist, 0755))
@@ -44,16 +45,18 @@ func TestRunPipe(t *testing.T) {
 				config.Project{
 					Dist:        dist,
 					ProjectName: "foobar",
-					Archive: config.Archive{
-						NameTemplate: defaultNameTemplate,
-						Files: []string{
-							"README.*",
-							"./foo/**/*",
-						},
-						FormatOverrides: []config.FormatOverride{
-							{
-								Goos:   "windows",
-								Format: "zip",
+					Archives: []config.Archive{
+						{
+							NameTemplate: defaultNameTemplate,
+							Files: []config.File{
+								{Source: "README.*"},
+								{Source: "./foo/**/*"},
+							},
+							FormatOverrides: []config.FormatOverride{
+								{
+									Goos:   "windows",
+									Format: "zip",
+								},
 							},
 						},
 					},
@@ -84,7 +87,7 @@ func TestRunPipe(t *testing.T) {
 			ctx.Artifacts.Add(windowsBuild)
 			ctx.Version = "0.0.1"
 			ctx.Git.CurrentTag = "v0.0.1"
-			ctx.Config.Archive.Format = format
+			ctx.Config.Archives[0].Format = format
 			require.NoError(tt, Pipe{}.Run(ctx))
 			var archives = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive))
 			require.Len(tt, archives.List(), 2)
@@ -175,9 +178,11 @@ func TestRunPipeBinary(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: dist,
-			Archive: config.Archive{
-				Format:       "binary",
-				NameTemplate: defaultBinaryNameTemplate,
+			Archives: []config.Archive{
+				{
+					Format:       "binary",
+					NameTemplate: defaultBinaryNameTemplate,
+				},
 			},
 		},
 	)
@@ -217,9 +222,11 @@ func TestRunPipeDistRemoved(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: "/path/nope",
-			Archive: config.Archive{
-				NameTemplate: "nope",
-				Format:       "zip",
+			Archives: []config.Archive{
+				{
+					NameTemplate: "nope",
+					Format:       "zip",
+				},
 			},
 		},
 	)
@@ -249,11 +256,13 @@ func TestRunPipeInvalidGlob(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: dist,
-			Archive: config.Archive{
-				NameTemplate: "foo",
-				Format:       "zip",
-				Files: []string{
-					"[x-]",
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo",
+					Format:       "zip",
+					Files: []config.File{
+						{Source: "[x-]"},
+					},
 				},
 			},
 		},
@@ -283,9 +292,11 @@ func TestRunPipeInvalidNameTemplate(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: dist,
-			Archive: config.Archive{
-				NameTemplate: "foo{{ .fff }",
-				Format:       "zip",
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo{{ .fff }",
+					Format:       "zip",
+				},
 			},
 		},
 	)
@@ -314,10 +325,12 @@ func TestRunPipeInvalidWrapInDirectoryTemplate(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: dist,
-			Archive: config.Archive{
-				NameTemplate:    "foo",
-				WrapInDirectory: "foo{{ .fff }",
-				Format:          "zip",
+			Archives: []config.Archive{
+				{
+					NameTemplate:    "foo",
+					WrapInDirectory: "foo{{ .fff }",
+					Format:          "zip",
+				},
 			},
 		},
 	)
@@ -348,15 +361,17 @@ func TestRunPipeWrap(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
 			Dist: dist,
-			Archive: config.Archive{
-				NameTemplate:    "foo",
-				WrapInDirectory: "foo_{{ .Os }}",
-				Format:          "tar.gz",
-				Replacements: map[string]string{
-					"darwin": "macOS",
-				},
-				Files: []string{
-					"README.*",
+			Archives: []config.Archive{
+				{
+					NameTemplate:    "foo",
+					WrapInDirectory: "foo_{{ .Os }}",
+					Format:          "tar.gz",
+					Replacements: map[string]string{
+						"darwin": "macOS",
+					},
+					Files: []config.File{
+						{Source: "README.*"},
+					},
 				},
 			},
 		},
@@ -392,64 +407,412 @@ func TestRunPipeWrap(t *testing.T) {
 	}
 }
 
+func TestRunPipeBuilds(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "linuxamd64"), 0755))
+	var ctx = context.New(
+		config.Project{
+			Dist: dist,
+			Archives: []config.Archive{
+				{
+					Format:       "tar.gz",
+					NameTemplate: defaultNameTemplate,
+					IDs:          []string{"server"},
+				},
+			},
+		},
+	)
+	ctx.Version = "0.0.1"
+	ctx.Git.CurrentTag = "v0.0.1"
+	for _, id := range []string{"server", "client"} {
+		var path = filepath.Join(dist, "linuxamd64", id)
+		_, err := os.Create(path)
+		require.NoError(t, err)
+		ctx.Artifacts.Add(artifact.Artifact{
+			Goos:   "linux",
+			Goarch: "amd64",
+			Name:   id,
+			Path:   path,
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				"Binary": id,
+				"ID":     id,
+			},
+		})
+	}
+	require.NoError(t, Pipe{}.Run(ctx))
+	var archives = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive)).List()
+	require.Len(t, archives, 1)
+	var builds = archives[0].Extra["Builds"].([]artifact.Artifact)
+	require.Len(t, builds, 1)
+	require.Equal(t, "server", builds[0].Name)
+}
+
+func TestRunPipeGz(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "linuxamd64"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dist, "linuxamd64", "mybin"), []byte("fake bin"), 0755))
+	_, err := os.Create(filepath.Join(folder, "README.md"))
+	require.NoError(t, err)
+	var ctx = context.New(
+		config.Project{
+			Dist:        dist,
+			ProjectName: "foobar",
+			Archives: []config.Archive{
+				{
+					NameTemplate:     "{{ .ProjectName }}_{{ .Os }}",
+					WrapInDirectory:  "true",
+					Format:           "gz",
+					CompressionLevel: 9,
+					Files:            []config.File{{Source: "README.*"}},
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join(dist, "linuxamd64", "mybin"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Binary": "mybin",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+	var archives = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive)).List()
+	require.Len(t, archives, 1)
+	require.Equal(t, "foobar_linux.gz", archives[0].Name)
+
+	f, err := os.Open(archives[0].Path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	require.Equal(t, "mybin", gr.Name)
+	bts, err := ioutil.ReadAll(gr)
+	require.NoError(t, err)
+	require.Equal(t, "fake bin", string(bts))
+}
+
+func TestRunPipeGzMultipleBinaries(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	var ctx = context.New(
+		config.Project{
+			Dist:        dist,
+			ProjectName: "foobar",
+			Archives: []config.Archive{
+				{
+					ID:           "default",
+					NameTemplate: "{{ .ProjectName }}_{{ .Os }}",
+					Format:       "gz",
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	for _, name := range []string{"foo", "bar"} {
+		ctx.Artifacts.Add(artifact.Artifact{
+			Goos:   "linux",
+			Goarch: "amd64",
+			Name:   name,
+			Path:   filepath.Join(dist, name),
+			Type:   artifact.Binary,
+		})
+	}
+	require.EqualError(t, Pipe{}.Run(ctx), "archive default: gz archives can only hold a single binary, but linuxamd64 has 2, use ids to select one build")
+	require.Empty(t, ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive)).List())
+}
+
+func TestRunPipeInvalidFormat(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	var ctx = context.New(
+		config.Project{
+			Dist: dist,
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo",
+					Format:       "rar",
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join(dist, "mybin"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Binary": "mybin",
+		},
+	})
+	require.EqualError(t, Pipe{}.Run(ctx), "invalid archive format: rar")
+}
+
+func TestRunPipeReproducible(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "zzz.txt"), []byte("z"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "mybin"), []byte("fake bin"), 0700))
+	var create = func(format string, now time.Time) string {
+		for _, f := range []string{"zzz.txt", "mybin"} {
+			require.NoError(t, os.Chtimes(filepath.Join(folder, f), now, now))
+		}
+		dist, err := ioutil.TempDir(folder, "dist")
+		require.NoError(t, err)
+		var ctx = context.New(
+			config.Project{
+				Dist: dist,
+				Archives: []config.Archive{
+					{
+						NameTemplate: "foo",
+						Format:       format,
+						Files:        []config.File{{Source: "zzz.txt"}},
+						Reproducible: true,
+					},
+				},
+			},
+		)
+		ctx.Git.CurrentTag = "v0.0.1"
+		ctx.Git.CommitDate = time.Date(2019, 1, 2, 3, 4, 6, 0, time.UTC)
+		ctx.Artifacts.Add(artifact.Artifact{
+			Goos:   "linux",
+			Goarch: "amd64",
+			Name:   "mybin",
+			Path:   filepath.Join(folder, "mybin"),
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				"Binary": "mybin",
+			},
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		return filepath.Join(dist, "foo."+format)
+	}
+	for _, format := range []string{"tar.gz", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			var now = time.Now()
+			first, err := ioutil.ReadFile(create(format, now))
+			require.NoError(t, err)
+			second, err := ioutil.ReadFile(create(format, now.Add(time.Hour)))
+			require.NoError(t, err)
+			require.Equal(t, first, second)
+		})
+	}
+	require.Equal(t, []string{"mybin", "zzz.txt"}, tarFiles(t, create("tar.gz", time.Now())))
+}
+
+func TestSourceDate(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	ctx.Git.CommitDate = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	date, err := sourceDate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ctx.Git.CommitDate, date)
+
+	ctx.Env["SOURCE_DATE_EPOCH"] = "1234567890"
+	date, err = sourceDate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1234567890, 0).UTC(), date)
+
+	ctx.Env["SOURCE_DATE_EPOCH"] = "yesterday"
+	_, err = sourceDate(ctx)
+	require.EqualError(t, err, "invalid SOURCE_DATE_EPOCH: yesterday")
+}
+
+func TestRunPipeMultipleArchives(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "windowsamd64"), 0755))
+	_, err := os.Create(filepath.Join(dist, "windowsamd64", "mybin.exe"))
+	require.NoError(t, err)
+	_, err = os.Create(filepath.Join(folder, "README.md"))
+	require.NoError(t, err)
+	var ctx = context.New(
+		config.Project{
+			Dist:        dist,
+			ProjectName: "foobar",
+			Archives: []config.Archive{
+				{
+					ID:           "slim",
+					NameTemplate: "{{ .ProjectName }}_slim_{{ .Os }}",
+					Format:       "zip",
+				},
+				{
+					ID:           "full",
+					NameTemplate: "{{ .ProjectName }}_{{ .Os }}",
+					Format:       "tar.gz",
+					Files:        []config.File{{Source: "README.*"}},
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Goos:   "windows",
+		Goarch: "amd64",
+		Name:   "mybin.exe",
+		Path:   filepath.Join(dist, "windowsamd64", "mybin.exe"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Binary": "mybin",
+			"Ext":    ".exe",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+	var archives = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive))
+	require.Len(t, archives.List(), 2)
+	slim := archives.Filter(artifact.ByIDs("slim")).List()[0]
+	full := archives.Filter(artifact.ByIDs("full")).List()[0]
+	require.Equal(t, "foobar_slim_windows.zip", slim.Name)
+	require.Equal(t, "foobar_windows.tar.gz", full.Name)
+	require.Equal(t, []string{"mybin.exe"}, zipFiles(t, slim.Path))
+	require.Equal(t, []string{"README.md", "mybin.exe"}, tarFiles(t, full.Path))
+}
+
 func TestDefault(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
-			Archive: config.Archive{},
+			Archives: []config.Archive{
+				{},
+			},
 		},
 	}
 	require.NoError(t, Pipe{}.Default(ctx))
-	require.NotEmpty(t, ctx.Config.Archive.NameTemplate)
-	require.Equal(t, "tar.gz", ctx.Config.Archive.Format)
-	require.NotEmpty(t, ctx.Config.Archive.Files)
+	require.Equal(t, "default", ctx.Config.Archives[0].ID)
+	require.NotEmpty(t, ctx.Config.Archives[0].NameTemplate)
+	require.Equal(t, "tar.gz", ctx.Config.Archives[0].Format)
+	require.NotEmpty(t, ctx.Config.Archives[0].Files)
 }
 
-func TestDefaultSet(t *testing.T) {
+func TestDefaultSingleArchive(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
 			Archive: config.Archive{
-				NameTemplate: "foo",
-				Format:       "zip",
-				Files: []string{
-					"foo",
-				},
+				Format: "zip",
 			},
 		},
 	}
 	require.NoError(t, Pipe{}.Default(ctx))
-	require.Equal(t, "foo", ctx.Config.Archive.NameTemplate)
-	require.Equal(t, "zip", ctx.Config.Archive.Format)
-	require.Equal(t, "foo", ctx.Config.Archive.Files[0])
+	require.Len(t, ctx.Config.Archives, 1)
+	require.Equal(t, "default", ctx.Config.Archives[0].ID)
+	require.Equal(t, "zip", ctx.Config.Archives[0].Format)
 }
 
-func TestDefaultFormatBinary(t *testing.T) {
+func TestDefaultDuplicateIDs(t *testing.T) {
+	var ctx = &context.Context{
+		Config: config.Project{
+			Archives: []config.Archive{
+				{ID: "foo"},
+				{ID: "bar"},
+				{ID: "foo"},
+			},
+		},
+	}
+	require.EqualError(t, Pipe{}.Default(ctx), "found 2 archives with the ID 'foo', please fix your config")
+}
+
+func TestDefaultArchiveAndArchives(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
 			Archive: config.Archive{
-				Format: "binary",
+				Format: "zip",
+			},
+			Archives: []config.Archive{
+				{ID: "foo"},
+			},
+		},
+	}
+	require.EqualError(t, Pipe{}.Default(ctx), ErrArchiveAndArchives.Error())
+}
+
+func TestDefaultGzMultipleBuilds(t *testing.T) {
+	var ctx = &context.Context{
+		Config: config.Project{
+			Builds: []config.Build{
+				{Binary: "foo"},
+				{Binary: "bar", ID: "server"},
+			},
+			Archives: []config.Archive{
+				{Format: "gz"},
 			},
 		},
 	}
+	require.EqualError(t, Pipe{}.Default(ctx), "archive default: gz archives can only hold a single binary, but 2 builds are selected, use ids to select one")
+
+	ctx.Config.Archives[0].IDs = []string{"server"}
 	require.NoError(t, Pipe{}.Default(ctx))
-	require.Equal(t, defaultBinaryNameTemplate, ctx.Config.Archive.NameTemplate)
 }
 
-func TestFormatFor(t *testing.T) {
+func TestDefaultSet(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
-			Archive: config.Archive{
-				Format: "tar.gz",
-				FormatOverrides: []config.FormatOverride{
-					{
-						Goos:   "windows",
-						Format: "zip",
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo",
+					Format:       "zip",
+					Files: []config.File{
+						{Source: "foo"},
 					},
 				},
 			},
 		},
 	}
-	require.Equal(t, "zip", packageFormat(ctx, "windows"))
-	require.Equal(t, "tar.gz", packageFormat(ctx, "linux"))
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, "foo", ctx.Config.Archives[0].NameTemplate)
+	require.Equal(t, "zip", ctx.Config.Archives[0].Format)
+	require.Equal(t, "foo", ctx.Config.Archives[0].Files[0].Source)
+}
+
+func TestDefaultFormatBinary(t *testing.T) {
+	var ctx = &context.Context{
+		Config: config.Project{
+			Archives: []config.Archive{
+				{
+					Format: "binary",
+				},
+			},
+		},
+	}
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, defaultBinaryNameTemplate, ctx.Config.Archives[0].NameTemplate)
+}
+
+func TestFormatFor(t *testing.T) {
+	var archive = config.Archive{
+		Format:           "tar.xz",
+		CompressionLevel: 9,
+		FormatOverrides: []config.FormatOverride{
+			{
+				Goos:             "windows",
+				Format:           "zip",
+				CompressionLevel: 5,
+			},
+		},
+	}
+	require.Equal(t, "zip", packageFormat(archive, "windows"))
+	require.Equal(t, "tar.xz", packageFormat(archive, "linux"))
+	format, level := formatAndLevel(archive, "windows")
+	require.Equal(t, "zip", format)
+	require.Equal(t, 5, level)
+	format, level = formatAndLevel(archive, "linux")
+	require.Equal(t, "tar.xz", format)
+	require.Equal(t, 9, level)
 }
 
 func TestBinaryOverride(t *testing.T) {
@@ -471,15 +834,17 @@ func TestBinaryOverride(t *testing.T) {
 				config.Project{
 					Dist:        dist,
 					ProjectName: "foobar",
-					Archive: config.Archive{
-						NameTemplate: defaultNameTemplate,
-						Files: []string{
-							"README.*",
-						},
-						FormatOverrides: []config.FormatOverride{
-							{
-								Goos:   "windows",
-								Format: "binary",
+					Archives: []config.Archive{
+						{
+							NameTemplate: defaultNameTemplate,
+							Files: []config.File{
+								{Source: "README.*"},
+							},
+							FormatOverrides: []config.FormatOverride{
+								{
+									Goos:   "windows",
+									Format: "binary",
+								},
 							},
 						},
 					},
@@ -508,7 +873,7 @@ func TestBinaryOverride(t *testing.T) {
 				},
 			})
 			ctx.Version = "0.0.1"
-			ctx.Config.Archive.Format = format
+			ctx.Config.Archives[0].Format = format
 
 			require.NoError(tt, Pipe{}.Run(ctx))
 			var archives = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive))
@@ -537,13 +902,15 @@ func TestRunPipeSameArchiveFilename(t *testing.T) {
 		config.Project{
 			Dist:        dist,
 			ProjectName: "foobar",
-			Archive: config.Archive{
-				NameTemplate: "same-filename",
-				Files: []string{
-					"README.*",
-					"./foo/**/*",
+			Archives: []config.Archive{
+				{
+					NameTemplate: "same-filename",
+					Files: []config.File{
+						{Source: "README.*"},
+						{Source: "./foo/**/*"},
+					},
+					Format: "tar.gz",
 				},
-				Format: "tar.gz",
 			},
 		},
 	)
@@ -586,7 +953,9 @@ func TestDuplicateFilesInsideArchive(t *testing.T) {
 	defer ff.Close()
 	defer os.Remove(ff.Name())
 
-	a := NewEnhancedArchive(archive.New(f), "")
+	af, err := archive.NewFormat(f, "tar.gz", 0)
+	require.NoError(t, err)
+	a := NewEnhancedArchive(af, "")
 	defer a.Close()
 	require.NoError(t, a.Add("foo", ff.Name()))
 	require.EqualError(t, a.Add("foo", ff.Name()), "file foo already exists in the archive")
@@ -610,3 +979,226 @@ func TestWrapInDirectory(t *testing.T) {
 		}))
 	})
 }
+
+func TestRunPipeFiles(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(folder, "docs", "linux"), 0755))
+	for _, f := range []string{"LICENSE.md", "docs/linux/foo.service", "docs/linux/bar.service", "docs/windows.txt", "mybin"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(folder, f), []byte(f), 0600))
+	}
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ctx = context.New(
+		config.Project{
+			Dist: dist,
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo_{{ .Os }}",
+					Format:       "tar.gz",
+					Files: []config.File{
+						{Source: "LICENSE.md", Destination: "LICENSE", Mode: 0644},
+						{Source: "docs/linux/*.service", Destination: "systemd/", StripParent: true, Goos: "linux"},
+						{Source: "docs/windows.txt", Goos: "windows"},
+						{Source: "docs/**/foo.service", Destination: "all", MTime: mtime.Format(time.RFC3339)},
+					},
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	for _, goos := range []string{"linux", "windows"} {
+		ctx.Artifacts.Add(artifact.Artifact{
+			Goos:   goos,
+			Goarch: "amd64",
+			Name:   "mybin",
+			Path:   filepath.Join(folder, "mybin"),
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				"Binary": "mybin",
+			},
+		})
+	}
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	var headers = tarHeaders(t, filepath.Join(dist, "foo_linux.tar.gz"))
+	require.Len(t, headers, 5)
+	require.Equal(t, int64(0644), headers["LICENSE"].Mode)
+	require.Contains(t, headers, "systemd/bar.service")
+	require.Contains(t, headers, "systemd/foo.service")
+	require.Contains(t, headers, "mybin")
+	require.Equal(t, mtime, headers["all/docs/linux/foo.service"].ModTime.UTC())
+
+	require.Equal(t, []string{
+		"LICENSE",
+		"all/docs/linux/foo.service",
+		"docs/windows.txt",
+		"mybin",
+	}, tarFiles(t, filepath.Join(dist, "foo_windows.tar.gz")))
+}
+
+func TestRunPipeFilesOutside(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "README.md"), []byte("readme"), 0600))
+	for _, dst := range []string{"../README.md", "docs/../../README.md", "/README.md"} {
+		var ctx = context.New(
+			config.Project{
+				Dist: folder,
+				Archives: []config.Archive{
+					{
+						NameTemplate: "foo",
+						Format:       "tar.gz",
+						Files: []config.File{
+							{Source: "README.md", Destination: dst},
+						},
+					},
+				},
+			},
+		)
+		ctx.Git.CurrentTag = "v0.0.1"
+		ctx.Artifacts.Add(artifact.Artifact{
+			Goos:   "linux",
+			Goarch: "amd64",
+			Name:   "mybin",
+			Path:   filepath.Join(folder, "README.md"),
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				"Binary": "mybin",
+			},
+		})
+		require.EqualError(t, Pipe{}.Run(ctx), "failed to find files to archive: failed to add README.md: dst "+dst+" is outside of the archive")
+		require.NoError(t, os.Remove(filepath.Join(folder, "foo.tar.gz")))
+	}
+}
+
+func TestRunPipeInvalidMTime(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "README.md"), []byte("readme"), 0600))
+	var ctx = context.New(
+		config.Project{
+			Dist: folder,
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo",
+					Format:       "tar.gz",
+					Files: []config.File{
+						{Source: "README.md", MTime: "yesterday"},
+					},
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join(folder, "README.md"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Binary": "mybin",
+		},
+	})
+	require.EqualError(t, Pipe{}.Run(ctx), `failed to find files to archive: failed to parse mtime of README.md: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`)
+}
+
+func tarHeaders(t *testing.T, path string) map[string]*tar.Header {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	defer gr.Close()
+	var r = tar.NewReader(gr)
+	var headers = map[string]*tar.Header{}
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		headers[next.Name] = next
+	}
+	return headers
+}
+
+func TestRunPipeTemplatedFiles(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "mybin"), []byte("fake bin"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "install.sh.tpl"), []byte("# {{ .ProjectName }} {{ .Version }} {{ .Os }}"), 0644))
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(folder, "install.sh.tpl"), mtime, mtime))
+	var ctx = context.New(
+		config.Project{
+			ProjectName: "proj",
+			Dist:        dist,
+			Archives: []config.Archive{
+				{
+					NameTemplate: "foo",
+					Format:       "tar.gz",
+					Replacements: map[string]string{"darwin": "macOS"},
+					TemplatedFiles: []config.TemplatedFile{
+						{Source: "install.sh.tpl", Destination: "scripts/install.sh", Mode: 0755},
+					},
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Version = "0.0.1"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Goos:   "darwin",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join(folder, "mybin"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Binary": "mybin",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	f, err := os.Open(filepath.Join(dist, "foo.tar.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	var r = tar.NewReader(gr)
+	var found bool
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if next.Name != "scripts/install.sh" {
+			continue
+		}
+		found = true
+		require.Equal(t, int64(0755), next.Mode)
+		require.True(t, mtime.Equal(next.ModTime), "should have the mtime of the template, got %s", next.ModTime)
+		bts, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "# proj 0.0.1 macOS", string(bts))
+	}
+	require.True(t, found)
+
+	ctx.Config.Archives[0].TemplatedFiles[0].Destination = "../install.sh"
+	require.NoError(t, os.Remove(filepath.Join(dist, "foo.tar.gz")))
+	require.Contains(t, Pipe{}.Run(ctx).Error(), "failed to template install.sh.tpl: dst ../install.sh is outside of the archive")
+
+	ctx.Config.Archives[0].TemplatedFiles[0].Destination = "/install.sh"
+	require.NoError(t, os.Remove(filepath.Join(dist, "foo.tar.gz")))
+	require.Contains(t, Pipe{}.Run(ctx).Error(), "failed to template install.sh.tpl: dst /install.sh is outside of the archive")
+
+	ctx.Config.Archives[0].TemplatedFiles[0].Source = "nope.tpl"
+	ctx.Config.Archives[0].TemplatedFiles[0].Destination = "scripts/install.sh"
+	require.NoError(t, os.Remove(filepath.Join(dist, "foo.tar.gz")))
+	require.Contains(t, Pipe{}.Run(ctx).Error(), "failed to template nope.tpl")
+}
@@ -34,7 +34,7 @@ func (Pipe) String() string {
 
 // Publish brew formula
 func (Pipe) Publish(ctx *context.Context) error {
-	client, err := client.NewGitHub(ctx)
+	client, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
@@ -46,7 +46,7 @@ func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Brew.Install == "" {
 		var installs []string
 		for _, build := range ctx.Config.Builds {
-			if !isBrewBuild(build) {
+			if !isBrewBuild(build) || !isSelected(ctx, build) {
 				continue
 			}
 			installs = append(
@@ -78,6 +78,21 @@ func isBrewBuild(build config.Build) bool {
 	return contains(build.Goos, "darwin") && contains(build.Goarch, "amd64")
 }
 
+// isSelected returns true if the build is selected by the brew IDs, either
+// directly or through one of the archives it is part of
+func isSelected(ctx *context.Context, build config.Build) bool {
+	var ids = ctx.Config.Brew.IDs
+	if len(ids) == 0 || contains(ids, build.ID) {
+		return true
+	}
+	for _, archive := range ctx.Config.Archives {
+		if contains(ids, archive.ID) && (len(archive.IDs) == 0 || contains(archive.IDs, build.ID)) {
+			return true
+		}
+	}
+	return false
+}
+
 func contains(ss []string, s string) bool {
 	for _, zs := range ss {
 		if zs == s {
@@ -88,10 +103,11 @@ func contains(ss []string, s string) bool {
 }
 
 func doRun(ctx *context.Context, client client.Client) error {
-	if ctx.Config.Brew.GitHub.Name == "" {
+	var tap = tapRepo(ctx)
+	if tap.Name == "" {
 		return pipe.Skip("brew section is not configured")
 	}
-	if getFormat(ctx) == "binary" {
+	if isBinaryRelease(ctx) {
 		return pipe.Skip("archive format is binary")
 	}
 
@@ -101,6 +117,7 @@ func doRun(ctx *context.Context, client client.Client) error {
 			artifact.ByGoarch("amd64"),
 			artifact.ByGoarm(""),
 			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByIDs(ctx.Config.Brew.IDs...),
 		),
 	).List()
 	if len(archives) == 0 {
@@ -134,24 +151,42 @@ func doRun(ctx *context.Context, client client.Client) error {
 
 	var gpath = ghFormulaPath(ctx.Config.Brew.Folder, filename)
 	log.WithField("formula", gpath).
-		WithField("repo", ctx.Config.Brew.GitHub.String()).
+		WithField("repo", tap.String()).
 		Info("pushing")
 
 	var msg = fmt.Sprintf("Brew formula update for %s version %s", ctx.Config.ProjectName, ctx.Git.CurrentTag)
-	return client.CreateFile(ctx, ctx.Config.Brew.CommitAuthor, ctx.Config.Brew.GitHub, content, gpath, msg)
+	return client.CreateFile(ctx, ctx.Config.Brew.CommitAuthor, tap, content, gpath, msg)
+}
+
+// tapRepo returns the tap repository on the provider the release is made on
+func tapRepo(ctx *context.Context) config.Repo {
+	if client.IsGitLab(ctx) {
+		return ctx.Config.Brew.GitLab
+	}
+	return ctx.Config.Brew.GitHub
 }
 
 func ghFormulaPath(folder, filename string) string {
 	return path.Join(folder, filename)
 }
 
-func getFormat(ctx *context.Context) string {
-	for _, override := range ctx.Config.Archive.FormatOverrides {
+// isBinaryRelease returns true if all archives ship raw darwin binaries
+func isBinaryRelease(ctx *context.Context) bool {
+	for _, archive := range ctx.Config.Archives {
+		if getFormat(archive) != "binary" {
+			return false
+		}
+	}
+	return len(ctx.Config.Archives) > 0
+}
+
+func getFormat(archive config.Archive) string {
+	for _, override := range archive.FormatOverrides {
 		if strings.HasPrefix("darwin", override.Goos) {
 			return override.Format
 		}
 	}
-	return ctx.Config.Archive.Format
+	return archive.Format
 }
 
 func buildFormula(ctx *context.Context, artifact artifact.Artifact) (bytes.Buffer, error) {
@@ -172,17 +207,14 @@ func doBuildFormula(data templateData) (out bytes.Buffer, err error) {
 }
 
 func dataFor(ctx *context.Context, artifact artifact.Artifact) (result templateData, err error) {
-	sum, err := artifact.Checksum()
+	sum, err := ctx.Artifacts.Checksum(artifact, "sha256")
 	if err != nil {
 		return
 	}
 	var cfg = ctx.Config.Brew
 
 	if ctx.Config.Brew.URLTemplate == "" {
-		ctx.Config.Brew.URLTemplate = fmt.Sprintf("%s/%s/%s/releases/download/{{ .Tag }}/{{ .ArtifactName }}",
-			ctx.Config.GitHubURLs.Download,
-			ctx.Config.Release.GitHub.Owner,
-			ctx.Config.Release.GitHub.Name)
+		ctx.Config.Brew.URLTemplate = client.ReleaseURLTemplate(ctx)
 	}
 	url, err := tmpl.New(ctx).WithArtifact(artifact, map[string]string{}).Apply(ctx.Config.Brew.URLTemplate)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -107,8 +108,8 @@ func TestRunPipe(t *testing.T) {
 			ctx.Config.Brew.CustomRequire = "custom_download_strategy"
 		},
 		"binary_overridden": func(ctx *context.Context) {
-			ctx.Config.Archive.Format = "binary"
-			ctx.Config.Archive.FormatOverrides = []config.FormatOverride{
+			ctx.Config.Archives[0].Format = "binary"
+			ctx.Config.Archives[0].FormatOverrides = []config.FormatOverride{
 				{
 					Goos:   "darwin",
 					Format: "zip",
@@ -118,6 +119,11 @@ func TestRunPipe(t *testing.T) {
 		"custom_block": func(ctx *context.Context) {
 			ctx.Config.Brew.CustomBlock = `head "https://github.com/caarlos0/test.git"`
 		},
+		"gitlab": func(ctx *context.Context) {
+			ctx.Config.GitLabURLs.Download = "https://gitlab.com"
+			ctx.Config.Release.GitLab = config.Repo{Owner: "test", Name: "test"}
+			ctx.Config.Brew.GitLab = config.Repo{Owner: "test", Name: "homebrew-test"}
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			folder, err := ioutil.TempDir("", "goreleasertest")
@@ -134,8 +140,10 @@ func TestRunPipe(t *testing.T) {
 					GitHubURLs: config.GitHubURLs{
 						Download: "https://github.com",
 					},
-					Archive: config.Archive{
-						Format: "tar.gz",
+					Archives: []config.Archive{
+						{
+							Format: "tar.gz",
+						},
 					},
 					Release: config.Release{
 						GitHub: config.Repo{
@@ -161,7 +169,7 @@ func TestRunPipe(t *testing.T) {
 				},
 			}
 			fn(ctx)
-			var format = getFormat(ctx)
+			var format = getFormat(ctx.Config.Archives[0])
 			var path = filepath.Join(folder, "bin."+format)
 			ctx.Artifacts.Add(artifact.Artifact{
 				Name:   "bin." + format,
@@ -196,8 +204,10 @@ func TestRunPipe(t *testing.T) {
 func TestRunPipeNoDarwin64Build(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
-			Archive: config.Archive{
-				Format: "tar.gz",
+			Archives: []config.Archive{
+				{
+					Format: "tar.gz",
+				},
 			},
 			Brew: config.Homebrew{
 				GitHub: config.Repo{
@@ -215,8 +225,10 @@ func TestRunPipeNoDarwin64Build(t *testing.T) {
 func TestRunPipeMultipleDarwin64Build(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
-			Archive: config.Archive{
-				Format: "tar.gz",
+			Archives: []config.Archive{
+				{
+					Format: "tar.gz",
+				},
 			},
 			Brew: config.Homebrew{
 				GitHub: config.Repo{
@@ -254,11 +266,39 @@ func TestRunPipeBrewNotSetup(t *testing.T) {
 	assert.False(t, client.CreatedFile)
 }
 
+func TestTapRepo(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Brew: config.Homebrew{
+			GitHub: config.Repo{Owner: "test", Name: "github-tap"},
+			GitLab: config.Repo{Owner: "test", Name: "gitlab-tap"},
+		},
+	})
+	assert.Equal(t, "test/github-tap", tapRepo(ctx).String())
+	ctx.Config.Release.GitLab = config.Repo{Owner: "test", Name: "test"}
+	assert.Equal(t, "test/gitlab-tap", tapRepo(ctx).String())
+}
+
+func TestRunPipeGitLabTapNotSetup(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Release: config.Release{
+			GitLab: config.Repo{Owner: "test", Name: "test"},
+		},
+		Brew: config.Homebrew{
+			GitHub: config.Repo{Owner: "test", Name: "github-tap"},
+		},
+	})
+	client := &DummyClient{}
+	testlib.AssertSkipped(t, doRun(ctx, client))
+	assert.False(t, client.CreatedFile)
+}
+
 func TestRunPipeBinaryRelease(t *testing.T) {
 	var ctx = context.New(
 		config.Project{
-			Archive: config.Archive{
-				Format: "binary",
+			Archives: []config.Archive{
+				{
+					Format: "binary",
+				},
 			},
 			Brew: config.Homebrew{
 				GitHub: config.Repo{
@@ -334,8 +374,10 @@ func TestRunPipeNoUpload(t *testing.T) {
 func TestRunPipeFormatBinary(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
-			Archive: config.Archive{
-				Format: "binary",
+			Archives: []config.Archive{
+				{
+					Format: "binary",
+				},
 			},
 		},
 	}
@@ -380,6 +422,45 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, `bin.install "foo"`, ctx.Config.Brew.Install)
 }
 
+func TestDefaultIDs(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+
+	var ctx = &context.Context{
+		Config: config.Project{
+			ProjectName: "myproject",
+			Builds: []config.Build{
+				{
+					ID:     "foo",
+					Binary: "foo",
+					Goos:   []string{"darwin"},
+					Goarch: []string{"amd64"},
+				},
+				{
+					ID:     "bar",
+					Binary: "bar",
+					Goos:   []string{"darwin"},
+					Goarch: []string{"amd64"},
+				},
+			},
+			Brew: config.Homebrew{
+				IDs: []string{"bar"},
+			},
+		},
+	}
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, `bin.install "bar"`, ctx.Config.Brew.Install)
+
+	ctx.Config.Brew.Install = ""
+	ctx.Config.Brew.IDs = []string{"slim"}
+	ctx.Config.Archives = []config.Archive{
+		{ID: "slim", IDs: []string{"foo"}},
+		{ID: "full"},
+	}
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, `bin.install "foo"`, ctx.Config.Brew.Install)
+}
+
 func TestGHFolder(t *testing.T) {
 	assert.Equal(t, "bar.rb", ghFormulaPath("", "bar.rb"))
 	assert.Equal(t, "fooo/bar.rb", ghFormulaPath("fooo", "bar.rb"))
@@ -394,6 +475,10 @@ func (client *DummyClient) CreateRelease(ctx *context.Context, body string) (rel
 	return
 }
 
+func (client *DummyClient) MergedPullRequests(ctx *context.Context, repo config.Repo, from, to string) (prs []client.PullRequest, err error) {
+	return
+}
+
 func (client *DummyClient) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo config.Repo, content bytes.Buffer, path, msg string) (err error) {
 	client.CreatedFile = true
 	bts, _ := ioutil.ReadAll(&content)
@@ -3,6 +3,7 @@
 package build
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,7 +19,7 @@ import (
 	"github.com/goreleaser/goreleaser/pkg/context"
 
 	// langs to init
-	_ "github.com/goreleaser/goreleaser/internal/builders/golang"
+	"github.com/goreleaser/goreleaser/internal/builders/golang"
 )
 
 // Pipe for build
@@ -41,12 +42,28 @@ func (Pipe) Run(ctx *context.Context) error {
 
 // Default sets the pipe defaults
 func (Pipe) Default(ctx *context.Context) error {
-	for i, build := range ctx.Config.Builds {
-		ctx.Config.Builds[i] = buildWithDefaults(ctx, build)
-	}
 	if len(ctx.Config.Builds) == 0 {
-		ctx.Config.Builds = []config.Build{
-			buildWithDefaults(ctx, ctx.Config.SingleBuild),
+		ctx.Config.Builds = []config.Build{ctx.Config.SingleBuild}
+	}
+	var builds []config.Build
+	for _, build := range ctx.Config.Builds {
+		expanded, err := expand(build)
+		if err != nil {
+			return err
+		}
+		for _, build := range expanded {
+			builds = append(builds, buildWithDefaults(ctx, build))
+		}
+	}
+	ctx.Config.Builds = builds
+	// archives and builds are both selected by ids, so they can't share them
+	var archives = map[string]bool{}
+	for _, archive := range ctx.Config.Archives {
+		archives[archive.ID] = true
+	}
+	for _, build := range ctx.Config.Builds {
+		if archives[build.ID] {
+			return fmt.Errorf("found a build and an archive with the ID '%s', please fix your config", build.ID)
 		}
 	}
 	if len(ctx.Config.Builds) > 1 {
@@ -55,6 +72,49 @@ func (Pipe) Default(ctx *context.Context) error {
 	return nil
 }
 
+// expand expands a build whose main is a pattern into one build per
+// matching main package, naming each binary after its folder by default.
+func expand(build config.Build) ([]config.Build, error) {
+	if !golang.IsMainPattern(build.Main) {
+		return []config.Build{build}, nil
+	}
+	mains, err := golang.MainPackages(build.Main)
+	if err != nil {
+		return nil, err
+	}
+	if len(mains) == 0 {
+		return nil, fmt.Errorf("no main packages found matching %s", build.Main)
+	}
+	if build.Binary != "" && len(mains) > 1 {
+		return nil, fmt.Errorf("binary can't be set when main %s matches more than one package", build.Main)
+	}
+	if build.ID != "" && len(mains) > 1 {
+		return nil, fmt.Errorf("id can't be set when main %s matches more than one package", build.Main)
+	}
+	var result = make([]config.Build, 0, len(mains))
+	for _, main := range mains {
+		var b = build
+		b.Main = main
+		if b.Binary == "" {
+			b.Binary = filepath.Base(main)
+		}
+		// avoid sharing the same underlying arrays between builds
+		b.Goos = append([]string(nil), build.Goos...)
+		b.Goarch = append([]string(nil), build.Goarch...)
+		b.Goarm = append([]string(nil), build.Goarm...)
+		b.Targets = append([]string(nil), build.Targets...)
+		b.Ignore = append([]config.IgnoredBuild(nil), build.Ignore...)
+		b.Ldflags = append(config.StringArray(nil), build.Ldflags...)
+		b.Flags = append(config.FlagArray(nil), build.Flags...)
+		b.Env = append([]string(nil), build.Env...)
+		b.Asmflags = append(config.StringArray(nil), build.Asmflags...)
+		b.Gcflags = append(config.StringArray(nil), build.Gcflags...)
+		log.WithField("main", main).WithField("binary", b.Binary).Debug("expanded build")
+		result = append(result, b)
+	}
+	return result, nil
+}
+
 func buildWithDefaults(ctx *context.Context, build config.Build) config.Build {
 	if build.Lang == "" {
 		build.Lang = "go"
@@ -62,6 +122,9 @@ func buildWithDefaults(ctx *context.Context, build config.Build) config.Build {
 	if build.Binary == "" {
 		build.Binary = ctx.Config.ProjectName
 	}
+	if build.ID == "" {
+		build.ID = build.Binary
+	}
 	for k, v := range build.Env {
 		build.Env[k] = os.ExpandEnv(v)
 	}
@@ -2,6 +2,7 @@ package build
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -210,6 +211,7 @@ func TestDefaultEmptyBuild(t *testing.T) {
 	assert.NoError(t, Pipe{}.Default(ctx))
 	var build = ctx.Config.Builds[0]
 	assert.Equal(t, ctx.Config.ProjectName, build.Binary)
+	assert.Equal(t, ctx.Config.ProjectName, build.ID)
 	assert.Equal(t, ".", build.Main)
 	assert.Equal(t, []string{"linux", "darwin"}, build.Goos)
 	assert.Equal(t, []string{"amd64", "386"}, build.Goarch)
@@ -258,6 +260,34 @@ func TestDefaultPartialBuilds(t *testing.T) {
 	})
 }
 
+func TestDefaultID(t *testing.T) {
+	var ctx = &context.Context{
+		Config: config.Project{
+			Builds: []config.Build{
+				{Binary: "foo"},
+				{Binary: "bar", ID: "server"},
+			},
+		},
+	}
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, "foo", ctx.Config.Builds[0].ID)
+	assert.Equal(t, "server", ctx.Config.Builds[1].ID)
+}
+
+func TestDefaultIDSharedWithArchive(t *testing.T) {
+	var ctx = &context.Context{
+		Config: config.Project{
+			Builds: []config.Build{
+				{Binary: "foo"},
+			},
+			Archives: []config.Archive{
+				{ID: "foo"},
+			},
+		},
+	}
+	assert.EqualError(t, Pipe{}.Default(ctx), "found a build and an archive with the ID 'foo', please fix your config")
+}
+
 func TestDefaultFillSingleBuild(t *testing.T) {
 	_, back := testlib.Mktmp(t)
 	defer back()
@@ -275,6 +305,142 @@ func TestDefaultFillSingleBuild(t *testing.T) {
 	assert.Equal(t, ctx.Config.Builds[0].Binary, "foo")
 }
 
+func TestDefaultMainPattern(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	writeMains(t, folder, "cmd/foo", "cmd/bar", "cmd/sub/baz")
+	assert.NoError(t, os.MkdirAll(filepath.Join(folder, "cmd/lib"), 0755))
+
+	for pattern, expected := range map[string][]config.Build{
+		"./cmd/*": {
+			{Main: "./cmd/bar", Binary: "bar"},
+			{Main: "./cmd/foo", Binary: "foo"},
+		},
+		"cmd/*": {
+			{Main: "./cmd/bar", Binary: "bar"},
+			{Main: "./cmd/foo", Binary: "foo"},
+		},
+		"./cmd/...": {
+			{Main: "./cmd/bar", Binary: "bar"},
+			{Main: "./cmd/foo", Binary: "foo"},
+			{Main: "./cmd/sub/baz", Binary: "baz"},
+		},
+		"./cmd/f*": {
+			{Main: "./cmd/foo", Binary: "foo"},
+		},
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			var ctx = context.New(config.Project{
+				ProjectName: "proj",
+				Builds: []config.Build{
+					{Main: pattern, Goos: []string{"linux"}, Env: []string{"FOO=bar"}},
+					{Binary: "single"},
+				},
+			})
+			assert.NoError(t, Pipe{}.Default(ctx))
+			assert.Len(t, ctx.Config.Builds, len(expected)+1)
+			for i, build := range expected {
+				assert.Equal(t, build.Main, ctx.Config.Builds[i].Main)
+				assert.Equal(t, build.Binary, ctx.Config.Builds[i].Binary)
+				assert.Equal(t, []string{"linux"}, ctx.Config.Builds[i].Goos)
+				assert.Equal(t, []string{"FOO=bar"}, ctx.Config.Builds[i].Env)
+			}
+			var last = ctx.Config.Builds[len(expected)]
+			assert.Equal(t, ".", last.Main)
+			assert.Equal(t, "single", last.Binary)
+		})
+	}
+}
+
+func TestDefaultMainPatternWithBinary(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	writeMains(t, folder, "cmd/foo", "cmd/bar")
+
+	var ctx = context.New(config.Project{
+		Builds: []config.Build{{Main: "./cmd/f*", Binary: "custom"}},
+	})
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Len(t, ctx.Config.Builds, 1)
+	assert.Equal(t, "custom", ctx.Config.Builds[0].Binary)
+
+	ctx = context.New(config.Project{
+		Builds: []config.Build{{Main: "./cmd/*", Binary: "custom"}},
+	})
+	assert.EqualError(t, Pipe{}.Default(ctx), "binary can't be set when main ./cmd/* matches more than one package")
+}
+
+func TestDefaultMainPatternWithID(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	writeMains(t, folder, "cmd/foo", "cmd/bar")
+
+	var ctx = context.New(config.Project{
+		Builds: []config.Build{{Main: "./cmd/f*", ID: "custom"}},
+	})
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, "custom", ctx.Config.Builds[0].ID)
+
+	ctx = context.New(config.Project{
+		Builds: []config.Build{{Main: "./cmd/*", ID: "custom"}},
+	})
+	assert.EqualError(t, Pipe{}.Default(ctx), "id can't be set when main ./cmd/* matches more than one package")
+}
+
+func TestDefaultMainPatternDoesNotShareSlices(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	writeMains(t, folder, "cmd/foo", "cmd/bar")
+
+	var build = config.Build{
+		Main:     "./cmd/*",
+		Goos:     []string{"linux", "darwin"},
+		Goarch:   []string{"amd64"},
+		Goarm:    []string{"6"},
+		Targets:  []string{"linux_amd64"},
+		Ignore:   []config.IgnoredBuild{{Goos: "darwin", Goarch: "386"}},
+		Ldflags:  []string{"-s"},
+		Flags:    []string{"-v"},
+		Env:      []string{"FOO=bar"},
+		Asmflags: []string{"all=-trimpath"},
+		Gcflags:  []string{"all=-trimpath"},
+	}
+	builds, err := expand(build)
+	assert.NoError(t, err)
+	assert.Len(t, builds, 2)
+	builds[0].Goos[0] = "windows"
+	builds[0].Goarch[0] = "arm"
+	builds[0].Goarm[0] = "7"
+	builds[0].Targets[0] = "windows_arm"
+	builds[0].Ignore[0].Goos = "windows"
+	builds[0].Ldflags[0] = "-w"
+	builds[0].Flags[0] = "-x"
+	builds[0].Env[0] = "FOO=baz"
+	builds[0].Asmflags[0] = "foo"
+	builds[0].Gcflags[0] = "foo"
+	for _, b := range []config.Build{build, builds[1]} {
+		assert.Equal(t, []string{"linux", "darwin"}, b.Goos)
+		assert.Equal(t, []string{"amd64"}, b.Goarch)
+		assert.Equal(t, []string{"6"}, b.Goarm)
+		assert.Equal(t, []string{"linux_amd64"}, b.Targets)
+		assert.Equal(t, "darwin", b.Ignore[0].Goos)
+		assert.Equal(t, config.StringArray{"-s"}, b.Ldflags)
+		assert.Equal(t, config.FlagArray{"-v"}, b.Flags)
+		assert.Equal(t, []string{"FOO=bar"}, b.Env)
+		assert.Equal(t, config.StringArray{"all=-trimpath"}, b.Asmflags)
+		assert.Equal(t, config.StringArray{"all=-trimpath"}, b.Gcflags)
+	}
+}
+
+func TestDefaultMainPatternNoMatches(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	var ctx = context.New(config.Project{
+		Builds: []config.Build{{Main: "./cmd/*"}},
+	})
+	assert.EqualError(t, Pipe{}.Default(ctx), "no main packages found matching ./cmd/*")
+}
+
 func TestExtWindows(t *testing.T) {
 	assert.Equal(t, ".exe", extFor("windows_amd64"))
 	assert.Equal(t, ".exe", extFor("windows_386"))
@@ -313,3 +479,14 @@ func exists(file string) bool {
 	_, err := os.Stat(file)
 	return !os.IsNotExist(err)
 }
+
+func writeMains(t *testing.T, folder string, dirs ...string) {
+	for _, dir := range dirs {
+		assert.NoError(t, os.MkdirAll(filepath.Join(folder, dir), 0755))
+		assert.NoError(t, ioutil.WriteFile(
+			filepath.Join(folder, dir, "main.go"),
+			[]byte("package main\nfunc main() {println(0)}"),
+			0644,
+		))
+	}
+}
@@ -2,18 +2,25 @@
 package changelog
 
 import (
-	"errors"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/textproto"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/apex/log"
+	"github.com/pkg/errors"
 
+	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -27,6 +34,24 @@ func (Pipe) String() string {
 	return "generating changelog"
 }
 
+// Default sets the pipe defaults
+func (Pipe) Default(ctx *context.Context) error {
+	var author = &ctx.Config.Changelog.File.CommitAuthor
+	if author.Name == "" {
+		author.Name = "goreleaserbot"
+	}
+	if author.Email == "" {
+		author.Email = "goreleaser@carlosbecker.com"
+	}
+	if ctx.Config.Changelog.Source == "" {
+		ctx.Config.Changelog.Source = "git"
+	}
+	if ctx.Config.Changelog.Contributors.Title == "" {
+		ctx.Config.Changelog.Contributors.Title = "Contributors"
+	}
+	return nil
+}
+
 // Run the pipe
 func (Pipe) Run(ctx *context.Context) error {
 	if ctx.ReleaseNotes != "" {
@@ -38,14 +63,113 @@ func (Pipe) Run(ctx *context.Context) error {
 	if err := checkSortDirection(ctx.Config.Changelog.Sort); err != nil {
 		return err
 	}
-	entries, err := buildChangelog(ctx)
+	data, err := build(ctx, ctx.Git.PreviousTag, ctx.Git.CurrentTag)
 	if err != nil {
 		return err
 	}
-	ctx.ReleaseNotes = fmt.Sprintf("## Changelog\n\n%v\n", strings.Join(entries, "\n"))
+	notes, err := markdown(ctx, data)
+	if err != nil {
+		return err
+	}
+	ctx.ReleaseNotes = notes
 	var path = filepath.Join(ctx.Config.Dist, "CHANGELOG.md")
 	log.WithField("changelog", path).Info("writing")
-	return ioutil.WriteFile(path, []byte(ctx.ReleaseNotes), 0644)
+	if err := ioutil.WriteFile(path, []byte(ctx.ReleaseNotes), 0644); err != nil {
+		return err
+	}
+	return checkFile(ctx)
+}
+
+// Generate returns the changelog between the given refs outside of a
+// release, either as markdown or as json. By default it goes from the tag
+// before the to ref, which defaults to HEAD.
+func Generate(ctx *context.Context, from, to, format string) (string, error) {
+	if format != "markdown" && format != "json" {
+		return "", fmt.Errorf("invalid changelog format: %s", format)
+	}
+	if err := checkSortDirection(ctx.Config.Changelog.Sort); err != nil {
+		return "", err
+	}
+	if ctx.Config.GitHubURLs.Download == "" {
+		ctx.Config.GitHubURLs.Download = "https://github.com"
+	}
+	var remote string
+	if client.ReleaseRepo(ctx).Name == "" {
+		// without a remote there are just no links
+		remote, _ = git.RemoteURL()
+	}
+	client.DefaultRepo(ctx, remote)
+	if to == "" {
+		to = "HEAD"
+	}
+	if from == "" {
+		from = git.PreviousTag(to)
+	}
+	data, err := build(ctx, from, to)
+	if err != nil {
+		return "", err
+	}
+	if format == "json" {
+		bts, err := json.MarshalIndent(data, "", "  ")
+		return string(bts) + "\n", err
+	}
+	return markdown(ctx, data)
+}
+
+// build returns the changelog between the given refs, from the beginning if
+// from is empty
+func build(ctx *context.Context, from, to string) (changelog, error) {
+	var err error
+	var data = changelog{
+		PreviousTag: from,
+		CurrentTag:  to,
+	}
+	if from != "" {
+		data.CompareURL = repoURL(ctx, "compare", from+"..."+to)
+	}
+	switch ctx.Config.Changelog.Source {
+	case "", "git":
+		if data.Commits, err = buildChangelog(ctx, from, to); err != nil {
+			return data, err
+		}
+	case "github", "gitlab":
+		var c client.Client
+		if ctx.Config.Changelog.Source == "gitlab" {
+			c, err = client.NewGitLab(ctx, ctx.ChangelogToken)
+		} else {
+			c, err = client.NewGitHub(ctx, ctx.ChangelogToken)
+		}
+		if err != nil {
+			return data, err
+		}
+		if data.PullRequests, err = buildPullRequests(ctx, c, from, to); err != nil {
+			return data, err
+		}
+	default:
+		return data, fmt.Errorf("invalid changelog source: %s", ctx.Config.Changelog.Source)
+	}
+	if data.Groups, err = group(ctx.Config.Changelog.Groups, data.Commits, data.PullRequests); err != nil {
+		return data, err
+	}
+	if ctx.Config.Changelog.Contributors.Enabled {
+		if data.Contributors, err = buildContributors(ctx, from, to); err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+// markdown renders the changelog and, unless a custom template renders the
+// contributors itself, their section
+func markdown(ctx *context.Context, data changelog) (string, error) {
+	notes, err := render(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	if ctx.Config.Changelog.Contributors.Enabled && ctx.Config.Changelog.Template == "" {
+		notes += renderContributors(ctx.Config.Changelog.Contributors, data.Contributors)
+	}
+	return notes, nil
 }
 
 func checkSortDirection(mode string) error {
@@ -60,13 +184,43 @@ func checkSortDirection(mode string) error {
 	return ErrInvalidSortDirection
 }
 
-func buildChangelog(ctx *context.Context) ([]string, error) {
-	log, err := getChangelog(ctx.Git.CurrentTag)
+// Commit is a changelog entry
+type Commit struct {
+	FullHash    string
+	ShortHash   string
+	Subject     string
+	Body        string
+	AuthorName  string
+	AuthorEmail string
+	Date        time.Time
+	// Trailers of the message, e.g. Signed-off-by, by their canonical key,
+	// e.g. Signed-Off-By
+	Trailers map[string][]string
+	// URL of the commit in the release repository
+	URL string
+}
+
+// String returns the changelog line of the commit
+func (c Commit) String() string {
+	return fmt.Sprintf("%s %s", c.ShortHash, c.Subject)
+}
+
+// message returns the whole commit message, so groups can match the footers
+func (c Commit) message() string {
+	return strings.TrimSpace(c.Subject + "\n\n" + c.Body)
+}
+
+// buildChangelog returns the filtered and sorted commits between the given
+// refs
+func buildChangelog(ctx *context.Context, from, to string) ([]Commit, error) {
+	log, err := getChangelog(from, to)
 	if err != nil {
 		return nil, err
 	}
-	var entries = strings.Split(log, "\n")
-	entries = entries[0 : len(entries)-1]
+	var entries = parseLog(log)
+	for i := range entries {
+		entries[i].URL = repoURL(ctx, "commit", entries[i].FullHash)
+	}
 	entries, err = filterEntries(ctx, entries)
 	if err != nil {
 		return entries, err
@@ -74,79 +228,242 @@ func buildChangelog(ctx *context.Context) ([]string, error) {
 	return sortEntries(ctx, entries), nil
 }
 
-func filterEntries(ctx *context.Context, entries []string) ([]string, error) {
-	for _, filter := range ctx.Config.Changelog.Filters.Exclude {
-		r, err := regexp.Compile(filter)
-		if err != nil {
-			return entries, err
+func filterEntries(ctx *context.Context, entries []Commit) ([]Commit, error) {
+	filters, err := excludeFilters(ctx)
+	if err != nil {
+		return entries, err
+	}
+	// nolint: prealloc
+	var result []Commit
+	for _, entry := range entries {
+		if !excluded(filters, entry.Subject) {
+			result = append(result, entry)
 		}
-		entries = remove(r, entries)
 	}
-	return entries, nil
+	return result, nil
 }
 
-func sortEntries(ctx *context.Context, entries []string) []string {
+func sortEntries(ctx *context.Context, entries []Commit) []Commit {
 	var direction = ctx.Config.Changelog.Sort
 	if direction == "" {
 		return entries
 	}
-	var result = make([]string, len(entries))
+	var result = make([]Commit, len(entries))
 	copy(result, entries)
-	sort.Slice(result, func(i, j int) bool {
-		_, imsg := extractCommitInfo(result[i])
-		_, jmsg := extractCommitInfo(result[j])
-		if direction == "asc" {
-			return strings.Compare(imsg, jmsg) < 0
-		}
-		return strings.Compare(imsg, jmsg) > 0
+	sort.SliceStable(result, func(i, j int) bool {
+		return less(direction, result[i].Subject, result[j].Subject)
 	})
 	return result
 }
 
-func remove(filter *regexp.Regexp, entries []string) (result []string) {
-	for _, entry := range entries {
-		_, msg := extractCommitInfo(entry)
-		if !filter.MatchString(msg) {
-			result = append(result, entry)
+func excludeFilters(ctx *context.Context) ([]*regexp.Regexp, error) {
+	// nolint: prealloc
+	var filters []*regexp.Regexp
+	for _, filter := range ctx.Config.Changelog.Filters.Exclude {
+		r, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, err
 		}
+		filters = append(filters, r)
 	}
-	return result
+	return filters, nil
+}
+
+func excluded(filters []*regexp.Regexp, subject string) bool {
+	for _, filter := range filters {
+		if filter.MatchString(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+func less(direction, a, b string) bool {
+	if direction == "asc" {
+		return strings.Compare(a, b) < 0
+	}
+	return strings.Compare(a, b) > 0
+}
+
+// changelog holds the data available to the changelog template
+type changelog struct {
+	PreviousTag  string
+	CurrentTag   string
+	CompareURL   string
+	Commits      []Commit
+	PullRequests []client.PullRequest
+	Groups       []Group
+	Contributors []Contributor
 }
 
-func extractCommitInfo(line string) (hash, msg string) {
-	ss := strings.Split(line, " ")
-	return ss[0], strings.Join(ss[1:], " ")
+// Group of changelog entries
+type Group struct {
+	Title        string
+	Commits      []Commit
+	PullRequests []client.PullRequest
+}
+
+// group puts each commit and pull request in the first group, by order,
+// matching it, and the ones matching none in a last Others group. Commits
+// match the regexp with their message, while pull requests match it with
+// their title or have one of the labels of the group. Empty groups are left
+// out. Without groups configured all entries are in a single group without
+// title.
+func group(cfgs []config.ChangelogGroup, commits []Commit, prs []client.PullRequest) ([]Group, error) {
+	if len(cfgs) == 0 {
+		return []Group{{Commits: commits, PullRequests: prs}}, nil
+	}
+	cfgs = append([]config.ChangelogGroup{}, cfgs...)
+	sort.SliceStable(cfgs, func(i, j int) bool {
+		return cfgs[i].Order < cfgs[j].Order
+	})
+	var groups = make([]Group, len(cfgs)+1)
+	var regexps = make([]*regexp.Regexp, len(cfgs))
+	for i, cfg := range cfgs {
+		groups[i].Title = cfg.Title
+		if cfg.Regexp == "" {
+			continue
+		}
+		r, err := regexp.Compile(cfg.Regexp)
+		if err != nil {
+			return nil, err
+		}
+		regexps[i] = r
+	}
+	groups[len(cfgs)].Title = "Others"
+	var matches = func(i int, s string) bool {
+		return regexps[i] != nil && regexps[i].MatchString(s)
+	}
+	for _, commit := range commits {
+		var i int
+		for i < len(cfgs) && !matches(i, commit.message()) {
+			i++
+		}
+		groups[i].Commits = append(groups[i].Commits, commit)
+	}
+	for _, pr := range prs {
+		var i int
+		for i < len(cfgs) && !matches(i, pr.Title) && !hasLabel(pr, cfgs[i].Labels) {
+			i++
+		}
+		groups[i].PullRequests = append(groups[i].PullRequests, pr)
+	}
+	// nolint: prealloc
+	var result []Group
+	for _, g := range groups {
+		if len(g.Commits) > 0 || len(g.PullRequests) > 0 {
+			result = append(result, g)
+		}
+	}
+	return result, nil
 }
 
-func getChangelog(tag string) (string, error) {
-	prev, err := previous(tag)
+const defaultTemplate = `## Changelog
+{{ range .Groups }}
+{{- with .Title }}
+### {{ . }}
+{{ end }}
+{{ range .Commits }}{{ .ShortHash }} {{ .Subject }}
+{{ end }}
+{{- range .PullRequests }}#{{ .Number }} {{ .Title }} (@{{ .Author }})
+{{ end }}
+{{- end }}`
+
+// render the changelog as markdown with the configured template, or with a
+// heading for each group by default
+func render(ctx *context.Context, data changelog) (string, error) {
+	var text = ctx.Config.Changelog.Template
+	if text == "" {
+		text = defaultTemplate
+	}
+	tmpl, err := template.New("changelog").Option("missingkey=error").Parse(text)
 	if err != nil {
-		return "", err
+		return "", errors.Wrap(err, "invalid changelog template")
 	}
-	if isSHA1(prev) {
-		return gitLog(prev, tag)
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", errors.Wrap(err, "failed to render changelog")
 	}
-	return gitLog(fmt.Sprintf("tags/%s..tags/%s", prev, tag))
+	return out.String(), nil
 }
 
-func gitLog(refs ...string) (string, error) {
-	var args = []string{"log", "--pretty=oneline", "--abbrev-commit", "--no-decorate", "--no-color"}
-	args = append(args, refs...)
-	return git.Run(args...)
+// repoURL returns the URL of the given page of the release repository, or
+// nothing if it is not known
+func repoURL(ctx *context.Context, page, path string) string {
+	var repo = client.ReleaseRepo(ctx)
+	var download = ctx.Config.GitHubURLs.Download
+	if client.IsGitLab(ctx) {
+		download = ctx.Config.GitLabURLs.Download
+		page = "-/" + page
+	}
+	if repo.Owner == "" || repo.Name == "" || download == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(download, "/"), repo.String(), page, path)
 }
 
-func previous(tag string) (result string, err error) {
-	result, err = git.Clean(git.Run("describe", "--tags", "--abbrev=0", fmt.Sprintf("tags/%s^", tag)))
-	if err != nil {
-		result, err = git.Clean(git.Run("rev-list", "--max-parents=0", "HEAD"))
+// nolint: gochecknoglobals
+var logFormat = strings.Join([]string{"%H", "%h", "%an", "%ae", "%aI", "%s", "%b"}, "%x1f") + "%x1e"
+
+// parseLog parses the commits of a git log using logFormat
+func parseLog(log string) []Commit {
+	// nolint: prealloc
+	var commits []Commit
+	for _, record := range strings.Split(log, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		var fields = strings.SplitN(record, "\x1f", 7)
+		for len(fields) < 7 {
+			fields = append(fields, "")
+		}
+		date, _ := time.Parse(time.RFC3339, fields[4])
+		var body = strings.TrimSpace(fields[6])
+		commits = append(commits, Commit{
+			FullHash:    fields[0],
+			ShortHash:   fields[1],
+			AuthorName:  fields[2],
+			AuthorEmail: fields[3],
+			Date:        date,
+			Subject:     fields[5],
+			Body:        body,
+			Trailers:    parseTrailers(body),
+		})
 	}
-	return
+	return commits
 }
 
 // nolint: gochecknoglobals
-var validSHA1 = regexp.MustCompile(`^[a-fA-F0-9]{40}$`)
+var trailer = regexp.MustCompile(`^([A-Za-z0-9-]+):\s*(.+)$`)
+
+// parseTrailers returns the trailers of the last paragraph of the body, if
+// all its lines are trailers
+func parseTrailers(body string) map[string][]string {
+	var paragraphs = strings.Split(body, "\n\n")
+	var trailers = map[string][]string{}
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		var match = trailer.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			return map[string][]string{}
+		}
+		var key = textproto.CanonicalMIMEHeaderKey(match[1])
+		trailers[key] = append(trailers[key], strings.TrimSpace(match[2]))
+	}
+	return trailers
+}
+
+// getChangelog returns the git log between the given refs, from the
+// beginning if from is empty
+func getChangelog(from, to string) (string, error) {
+	if from == "" {
+		return gitLog(git.TagRef(to))
+	}
+	return gitLog(fmt.Sprintf("%s..%s", git.TagRef(from), git.TagRef(to)))
+}
 
-// isSHA1 te lets us know if the ref is a SHA1 or not
-func isSHA1(ref string) bool {
-	return validSHA1.MatchString(ref)
+func gitLog(refs ...string) (string, error) {
+	var args = []string{"log", "--pretty=format:" + logFormat, "--no-decorate", "--no-color"}
+	args = append(args, refs...)
+	return git.Run(args...)
 }
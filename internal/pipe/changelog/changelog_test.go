@@ -1,12 +1,18 @@
 package changelog
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -56,6 +62,7 @@ func TestChangelog(t *testing.T) {
 			},
 		},
 	})
+	ctx.Git.PreviousTag = "v0.0.1"
 	ctx.Git.CurrentTag = "v0.0.2"
 	assert.NoError(t, Pipe{}.Run(ctx))
 	assert.Contains(t, ctx.ReleaseNotes, "## Changelog")
@@ -85,6 +92,7 @@ func TestChangelogSort(t *testing.T) {
 	var ctx = context.New(config.Project{
 		Changelog: config.Changelog{},
 	})
+	ctx.Git.PreviousTag = "v0.9.9"
 	ctx.Git.CurrentTag = "v1.0.0"
 
 	for _, cfg := range []struct {
@@ -118,19 +126,319 @@ func TestChangelogSort(t *testing.T) {
 	} {
 		t.Run("changelog sort='"+cfg.Sort+"'", func(t *testing.T) {
 			ctx.Config.Changelog.Sort = cfg.Sort
-			entries, err := buildChangelog(ctx)
+			entries, err := buildChangelog(ctx, "v0.9.9", "v1.0.0")
 			assert.NoError(t, err)
 			assert.Len(t, entries, len(cfg.Entries))
 			var changes []string
-			for _, line := range entries {
-				_, msg := extractCommitInfo(line)
-				changes = append(changes, msg)
+			for _, entry := range entries {
+				changes = append(changes, entry.Subject)
 			}
 			assert.EqualValues(t, cfg.Entries, changes)
 		})
 	}
 }
 
+func TestChangelogGroups(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1")
+	testlib.GitCommit(t, "fix: fixed bug 2")
+	testlib.GitCommit(t, "feat(api)!: removed the old api")
+	testlib.GitCommit(t, "docs: whatever")
+	testlib.GitCommit(t, "chore: bump deps")
+	testlib.GitCommit(t, "refactor: new config format\n\nBREAKING CHANGE: the config format changed")
+	testlib.GitCommit(t, "feat: added feature 3")
+	testlib.GitTag(t, "v0.0.2")
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Sort: "asc",
+			Filters: config.Filters{
+				Exclude: []string{"^docs:"},
+			},
+			Groups: []config.ChangelogGroup{
+				{Title: "Features", Regexp: "^feat", Order: 1},
+				{Title: "Bug fixes", Regexp: "^fix", Order: 2},
+				{Title: "Breaking changes", Regexp: "(?m)(^\\w+(\\(.*\\))?!:|^BREAKING CHANGE)", Order: 0},
+				{Title: "Performance", Regexp: "^perf", Order: 3},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	assert.NoError(t, Pipe{}.Run(ctx))
+
+	var lines []string
+	for _, line := range strings.Split(ctx.ReleaseNotes, "\n") {
+		if strings.Contains(line, " ") && !strings.HasPrefix(line, "#") {
+			_, msg := splitLine(line)
+			line = msg
+		}
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{
+		"## Changelog",
+		"",
+		"### Breaking changes",
+		"",
+		"feat(api)!: removed the old api",
+		"refactor: new config format",
+		"",
+		"### Features",
+		"",
+		"feat: added feature 1",
+		"feat: added feature 3",
+		"",
+		"### Bug fixes",
+		"",
+		"fix: fixed bug 2",
+		"",
+		"### Others",
+		"",
+		"chore: bump deps",
+		"",
+	}, lines)
+}
+
+func TestChangelogGroupsInvalidRegex(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Groups: []config.ChangelogGroup{
+				{Title: "Features", Regexp: "^feat("},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.1"
+	assert.EqualError(t, Pipe{}.Run(ctx), "error parsing regexp: missing closing ): `^feat(`")
+}
+
+func TestChangelogTemplate(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1\n\nsome details\n\nSigned-off-by: Foo <foo@bar>\nReviewed-by: Bar <bar@foo>")
+	testlib.GitTag(t, "v0.0.2")
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "goreleaser"},
+		},
+		GitHubURLs: config.GitHubURLs{Download: "https://github.com"},
+		Changelog: config.Changelog{
+			Template: `# {{ .PreviousTag }} to {{ .CurrentTag }}
+{{ range .Commits }}
+- [{{ .Subject }}]({{ .URL }}) {{ .Body }} by {{ .AuthorName }} <{{ .AuthorEmail }}> on {{ .Date.Year }}
+  {{- range index .Trailers "Signed-Off-By" }} signed off by {{ . }}{{ end }}
+{{- end }}
+
+{{ .CompareURL }}
+`,
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	assert.NoError(t, Pipe{}.Run(ctx))
+
+	hash, err := git.Clean(git.Run("rev-parse", "v0.0.2"))
+	assert.NoError(t, err)
+	var year = time.Now().Year()
+	assert.Equal(t, fmt.Sprintf(`# v0.0.1 to v0.0.2
+
+- [feat: added feature 1](https://github.com/goreleaser/goreleaser/commit/%s) some details
+
+Signed-off-by: Foo <foo@bar>
+Reviewed-by: Bar <bar@foo> by GoReleaser <test@goreleaser.github.com> on %d signed off by Foo <foo@bar>
+
+https://github.com/goreleaser/goreleaser/compare/v0.0.1...v0.0.2
+`, hash, year), ctx.ReleaseNotes)
+}
+
+func TestChangelogGitLabURLs(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1")
+	testlib.GitTag(t, "v0.0.2")
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		Release: config.Release{
+			GitLab: config.Repo{Owner: "group/subgroup", Name: "project"},
+		},
+		GitHubURLs: config.GitHubURLs{Download: "https://github.com"},
+		GitLabURLs: config.GitLabURLs{Download: "https://gitlab.example.com/"},
+		Changelog: config.Changelog{
+			Template: "{{ range .Commits }}{{ .URL }}\n{{ end }}{{ .CompareURL }}\n",
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	assert.NoError(t, Pipe{}.Run(ctx))
+
+	hash, err := git.Clean(git.Run("rev-parse", "v0.0.2"))
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`https://gitlab.example.com/group/subgroup/project/-/commit/%s
+https://gitlab.example.com/group/subgroup/project/-/compare/v0.0.1...v0.0.2
+`, hash), ctx.ReleaseNotes)
+}
+
+func TestGenerateGitLabRemote(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@gitlab.com:foo/bar.git")
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Template: "{{ .CompareURL }}\n",
+		},
+	})
+	out, err := Generate(ctx, "", "", "markdown")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/foo/bar/-/compare/v0.0.1...HEAD\n", out)
+	assert.Equal(t, config.Repo{Owner: "foo", Name: "bar"}, ctx.Config.Release.GitLab)
+	assert.Empty(t, ctx.Config.Release.GitHub.Name)
+}
+
+func TestChangelogInvalidTemplate(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Template: "{{ .Nope }",
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.1"
+	assert.EqualError(t, Pipe{}.Run(ctx), `invalid changelog template: template: changelog:1: unexpected "}" in operand`)
+
+	ctx.Config.Changelog.Template = "{{ .Nope }}"
+	assert.Error(t, Pipe{}.Run(ctx))
+}
+
+func TestParseTrailers(t *testing.T) {
+	for body, trailers := range map[string]map[string][]string{
+		"":            {},
+		"just a body": {},
+		"Signed-off-by: Foo <foo@bar>": {
+			"Signed-Off-By": {"Foo <foo@bar>"},
+		},
+		"some details\n\nFixes: #12\nCo-authored-by: Foo <foo@bar>\nco-authored-by: Bar <bar@foo>": {
+			"Fixes":          {"#12"},
+			"Co-Authored-By": {"Foo <foo@bar>", "Bar <bar@foo>"},
+		},
+		"Fixes: #12\nbut this is not a trailer": {},
+	} {
+		assert.Equal(t, trailers, parseTrailers(body), body)
+	}
+}
+
+func TestChangelogFromPullRequests(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "wip")
+	testlib.GitTag(t, "v0.0.2")
+
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/goreleaser/goreleaser/compare/v0.0.1...v0.0.2":
+			fmt.Fprint(w, `{"commits": [
+				{"sha": "aaa", "commit": {"committer": {"date": "2019-03-01T10:00:00Z"}}},
+				{"sha": "bbb", "commit": {"committer": {"date": "2019-03-02T10:00:00Z"}}},
+				{"sha": "ccc", "commit": {"committer": {"date": "2019-03-03T10:00:00Z"}}},
+				{"sha": "ddd", "commit": {"committer": {"date": "2019-03-04T10:00:00Z"}}}
+			]}`)
+		case "/repos/goreleaser/goreleaser/pulls":
+			assert.Equal(t, "closed", r.URL.Query().Get("state"))
+			fmt.Fprint(w, `[
+				{"number": 5, "title": "docs: typo", "merge_commit_sha": "ddd", "merged_at": "2019-03-04T10:00:00Z", "updated_at": "2019-03-04T10:00:00Z", "user": {"login": "carlos"}},
+				{"number": 4, "title": "Fix the thing", "merge_commit_sha": "ccc", "merged_at": "2019-03-03T10:00:00Z", "updated_at": "2019-03-03T10:00:00Z", "user": {"login": "bob"}, "labels": [{"name": "bug"}]},
+				{"number": 3, "title": "Add the other thing", "merge_commit_sha": "bbb", "merged_at": "2019-03-02T10:00:00Z", "updated_at": "2019-03-02T10:00:00Z", "user": {"login": "alice"}, "labels": [{"name": "enhancement"}]},
+				{"number": 6, "title": "Closed without merging", "updated_at": "2019-03-02T10:00:00Z", "user": {"login": "eve"}},
+				{"number": 2, "title": "Update deps", "merge_commit_sha": "aaa", "merged_at": "2019-03-01T10:00:00Z", "updated_at": "2019-03-01T10:00:00Z", "user": {"login": "dependabot"}, "labels": [{"name": "dependencies"}]},
+				{"number": 1, "title": "Merged before", "merge_commit_sha": "zzz", "merged_at": "2019-02-01T10:00:00Z", "updated_at": "2019-02-01T10:00:00Z", "user": {"login": "alice"}}
+			]`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "goreleaser"},
+		},
+		GitHubURLs: config.GitHubURLs{API: srv.URL + "/", Upload: srv.URL + "/"},
+		Changelog: config.Changelog{
+			Source: "github",
+			Filters: config.Filters{
+				Exclude: []string{"^docs:"},
+			},
+			Groups: []config.ChangelogGroup{
+				{Title: "Features", Labels: []string{"enhancement", "feature"}, Order: 0},
+				{Title: "Bug fixes", Labels: []string{"bug"}, Regexp: "^Fix", Order: 1},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	assert.NoError(t, Pipe{}.Run(ctx))
+	assert.Equal(t, `## Changelog
+
+### Features
+
+#3 Add the other thing (@alice)
+
+### Bug fixes
+
+#4 Fix the thing (@bob)
+
+### Others
+
+#2 Update deps (@dependabot)
+`, ctx.ReleaseNotes)
+}
+
+func TestChangelogInvalidSource(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Source: "svn",
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.1"
+	assert.EqualError(t, Pipe{}.Run(ctx), "invalid changelog source: svn")
+}
+
+func splitLine(line string) (hash, msg string) {
+	ss := strings.SplitN(line, " ", 2)
+	return ss[0], ss[1]
+}
+
 func TestChangelogInvalidSort(t *testing.T) {
 	var ctx = context.New(config.Project{
 		Changelog: config.Changelog{
@@ -180,6 +488,7 @@ func TestChangelogFilterInvalidRegex(t *testing.T) {
 			},
 		},
 	})
+	ctx.Git.PreviousTag = "v0.0.3"
 	ctx.Git.CurrentTag = "v0.0.4"
 	assert.EqualError(t, Pipe{}.Run(ctx), "error parsing regexp: invalid or unsupported Perl syntax: `(?ia`")
 }
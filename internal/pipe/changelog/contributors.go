@@ -0,0 +1,130 @@
+package changelog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Contributor of a release, either a commit author or co-author
+type Contributor struct {
+	Name  string
+	Email string
+	// Handle in the SCM, from the mapping file
+	Handle string
+}
+
+// String returns the contributor as listed in the release notes
+func (c Contributor) String() string {
+	if c.Handle != "" {
+		return "@" + c.Handle
+	}
+	return c.Name
+}
+
+func (c Contributor) sortKey() string {
+	if c.Handle != "" {
+		return strings.ToLower(c.Handle)
+	}
+	return strings.ToLower(c.Name)
+}
+
+// buildContributors returns the authors and co-authors of the commits
+// between the given refs, unique by email and sorted by handle or name
+func buildContributors(ctx *context.Context, from, to string) ([]Contributor, error) {
+	var cfg = ctx.Config.Changelog.Contributors
+	handles, err := loadMapping(cfg.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: prealloc
+	var excludes []*regexp.Regexp
+	for _, exclude := range cfg.Exclude {
+		r, err := regexp.Compile(exclude)
+		if err != nil {
+			return nil, err
+		}
+		excludes = append(excludes, r)
+	}
+	log, err := getChangelog(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var seen = map[string]bool{}
+	// nolint: prealloc
+	var result []Contributor
+	var add = func(name, email string) {
+		var key = strings.ToLower(email)
+		if email == "" || seen[key] || excluded(excludes, fmt.Sprintf("%s <%s>", name, email)) {
+			return
+		}
+		seen[key] = true
+		result = append(result, Contributor{
+			Name:   name,
+			Email:  email,
+			Handle: handles[key],
+		})
+	}
+	for _, commit := range parseLog(log) {
+		add(commit.AuthorName, commit.AuthorEmail)
+		for _, coauthor := range commit.Trailers["Co-Authored-By"] {
+			addr, err := mail.ParseAddress(coauthor)
+			if err != nil {
+				continue
+			}
+			add(addr.Name, addr.Address)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].sortKey() < result[j].sortKey()
+	})
+	return result, nil
+}
+
+// loadMapping loads the file mapping the emails of the contributors to
+// their handles
+func loadMapping(path string) (map[string]string, error) {
+	var handles = map[string]string{}
+	if path == "" {
+		return handles, nil
+	}
+	bts, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load contributors mapping")
+	}
+	var mapping map[string]string
+	if err := yaml.Unmarshal(bts, &mapping); err != nil {
+		return nil, errors.Wrap(err, "failed to load contributors mapping")
+	}
+	for email, handle := range mapping {
+		handles[strings.ToLower(email)] = strings.TrimPrefix(handle, "@")
+	}
+	return handles, nil
+}
+
+// renderContributors returns the markdown section listing the contributors
+func renderContributors(cfg config.ChangelogContributors, contributors []Contributor) string {
+	if len(contributors) == 0 {
+		return ""
+	}
+	var title = cfg.Title
+	if title == "" {
+		title = "Contributors"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## %s\n\n", title)
+	for _, c := range contributors {
+		fmt.Fprintf(&b, "- %s\n", c)
+	}
+	return b.String()
+}
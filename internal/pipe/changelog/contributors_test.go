@@ -0,0 +1,113 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func commitAs(t *testing.T, author, msg string) {
+	_, err := git.Run(
+		"-c", "user.name=GoReleaser",
+		"-c", "user.email=test@goreleaser.github.com",
+		"-c", "commit.gpgSign=false",
+		"commit", "--allow-empty", "--author", author, "-m", msg,
+	)
+	require.NoError(t, err)
+}
+
+func TestContributors(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	commitAs(t, "Old Timer <old@example.com>", "first")
+	testlib.GitTag(t, "v0.0.1")
+	commitAs(t, "Alice <alice@example.com>", "feat: added feature 1")
+	commitAs(t, "bob <bob@example.com>", "fix: fixed bug 2\n\nCo-authored-by: Carol <carol@example.com>\nCo-authored-by: Alice Again <ALICE@example.com>")
+	commitAs(t, "Alice <alice@example.com>", "feat: added feature 3")
+	commitAs(t, "goreleaserbot <goreleaser@carlosbecker.com>", "brew formula update")
+	commitAs(t, "dependabot[bot] <49699333+dependabot[bot]@users.noreply.github.com>", "bump deps\n\nco-authored-by: Dan <dan@example.com>")
+	testlib.GitTag(t, "v0.0.2")
+
+	var mapping = filepath.Join(folder, "contributors.yml")
+	require.NoError(t, ioutil.WriteFile(mapping, []byte("alice@example.com: alice\nCAROL@example.com: '@carol'\n"), 0644))
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Contributors: config.ChangelogContributors{
+				Enabled: true,
+				Mapping: mapping,
+				Exclude: []string{
+					`\[bot\]`,
+					"^goreleaserbot ",
+				},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	assert.Contains(t, ctx.ReleaseNotes, "bump deps")
+	assert.Contains(t, ctx.ReleaseNotes, `
+## Contributors
+
+- @alice
+- bob
+- @carol
+- Dan
+`)
+	assert.NotContains(t, ctx.ReleaseNotes, "Old Timer")
+}
+
+func TestContributorsTitleAndTemplate(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	commitAs(t, "Alice <alice@example.com>", "first")
+	testlib.GitTag(t, "v0.0.1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Template: "{{ range .Contributors }}{{ .Name }} <{{ .Email }}>{{ end }}\n",
+			Contributors: config.ChangelogContributors{
+				Enabled: true,
+				Title:   "Thanks",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.1"
+	require.NoError(t, Pipe{}.Run(ctx))
+	// custom templates render the contributors themselves, so the section
+	// isn't appended
+	assert.Equal(t, "Alice <alice@example.com>\n", ctx.ReleaseNotes)
+	assert.NotContains(t, ctx.ReleaseNotes, "Thanks")
+}
+
+func TestContributorsErrors(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	var ctx = context.New(config.Project{
+		Changelog: config.Changelog{
+			Contributors: config.ChangelogContributors{
+				Enabled: true,
+				Mapping: "nope.yml",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.1"
+	assert.EqualError(t, Pipe{}.Run(ctx), "failed to load contributors mapping: open nope.yml: no such file or directory")
+
+	ctx.Config.Changelog.Contributors.Mapping = ""
+	ctx.Config.Changelog.Contributors.Exclude = []string{"bot("}
+	assert.EqualError(t, Pipe{}.Run(ctx), "error parsing regexp: missing closing ): `bot(`")
+}
@@ -0,0 +1,143 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// nolint: gochecknoglobals
+var versionHeading = regexp.MustCompile(`(?m)^## \[?([^\]\s]+)\]?`)
+
+// Publish prepends the release notes to the changelog file, either in the
+// working tree or committing it to the repository, once the release is done
+func (Pipe) Publish(ctx *context.Context) error {
+	var cfg = ctx.Config.Changelog.File
+	if cfg.Path == "" {
+		return pipe.Skip("changelog file is disabled")
+	}
+	if !cfg.Commit {
+		return writeFile(ctx)
+	}
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return doPublish(ctx, c)
+}
+
+func doPublish(ctx *context.Context, c client.Client) error {
+	var cfg = ctx.Config.Changelog.File
+	content, err := updatedFile(ctx)
+	if err != nil {
+		return err
+	}
+	log.WithField("file", cfg.Path).Info("committing")
+	return c.CreateFile(
+		ctx,
+		cfg.CommitAuthor,
+		client.ReleaseRepo(ctx),
+		*bytes.NewBufferString(content),
+		cfg.Path,
+		fmt.Sprintf("Update %s for %s", cfg.Path, ctx.Git.CurrentTag),
+	)
+}
+
+// checkFile fails if the changelog file can't be updated, so it happens
+// before building anything instead of after the release
+func checkFile(ctx *context.Context) error {
+	if ctx.Config.Changelog.File.Path == "" {
+		return nil
+	}
+	_, err := updatedFile(ctx)
+	return err
+}
+
+// writeFile prepends the release notes to the changelog file in the working
+// tree
+func writeFile(ctx *context.Context) error {
+	var cfg = ctx.Config.Changelog.File
+	content, err := updatedFile(ctx)
+	if err != nil {
+		return err
+	}
+	log.WithField("file", cfg.Path).Info("writing")
+	return ioutil.WriteFile(cfg.Path, []byte(content), 0644)
+}
+
+// updatedFile returns the contents of the changelog file with a section for
+// the release prepended to the ones of the previous versions
+func updatedFile(ctx *context.Context) (string, error) {
+	var path = ctx.Config.Changelog.File.Path
+	bts, err := ioutil.ReadFile(path) // #nosec
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	var current = string(bts)
+	if strings.TrimSpace(current) == "" {
+		current = keepAChangelogHeader
+	}
+
+	var version = ctx.Version
+	if version == "" {
+		version = ctx.Git.CurrentTag
+	}
+	var idx = -1
+	for _, match := range versionHeading.FindAllStringSubmatchIndex(current, -1) {
+		var heading = current[match[2]:match[3]]
+		if heading == version || heading == ctx.Git.CurrentTag {
+			return "", fmt.Errorf("%s already has an entry for %s", path, version)
+		}
+		if idx == -1 && !strings.EqualFold(heading, "unreleased") {
+			idx = match[0]
+		}
+	}
+
+	var date = ctx.Git.CommitDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var section = fmt.Sprintf(
+		"## [%s] - %s\n\n%s\n",
+		version,
+		date.UTC().Format("2006-01-02"),
+		sectionBody(ctx.ReleaseNotes),
+	)
+	if idx == -1 {
+		return strings.TrimRight(current, "\n") + "\n\n" + section, nil
+	}
+	return current[:idx] + section + "\n" + current[idx:], nil
+}
+
+// sectionBody returns the release notes without their title and with the
+// other headings moved one level down, below the version heading
+func sectionBody(notes string) string {
+	var lines = strings.Split(strings.TrimSpace(notes), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "## ") {
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,218 @@
+package changelog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func TestDefault(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, "goreleaserbot", ctx.Config.Changelog.File.CommitAuthor.Name)
+	assert.Equal(t, "goreleaser@carlosbecker.com", ctx.Config.Changelog.File.CommitAuthor.Email)
+}
+
+func fileContext(t *testing.T, folder string, commit bool) *context.Context {
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v1.0.0")
+	testlib.GitCommit(t, "feat: added feature 1")
+	testlib.GitCommit(t, "fix: fixed bug 2")
+	testlib.GitTag(t, "v1.1.0")
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.MkdirAll(dist, 0755))
+	var ctx = context.New(config.Project{
+		Dist: dist,
+		Changelog: config.Changelog{
+			Template: "## Changelog\n\n### Added\n\n- feature 1\n\n### Fixed\n\n- bug 2\n\n## Contributors\n\n- @alice\n",
+			File: config.ChangelogFile{
+				Path:   filepath.Join(folder, "CHANGELOG.md"),
+				Commit: commit,
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v1.0.0"
+	ctx.Git.CurrentTag = "v1.1.0"
+	ctx.Git.CommitDate = time.Date(2019, 3, 18, 10, 0, 0, 0, time.UTC)
+	ctx.Version = "1.1.0"
+	return ctx
+}
+
+func TestFileCreated(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var ctx = fileContext(t, folder, false)
+	ctx.Config.Changelog.File.Path = filepath.Join(folder, "docs", "CHANGES.md")
+	require.NoError(t, os.Mkdir(filepath.Join(folder, "docs"), 0755))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	// the file is only written on publish, after the release
+	_, err := os.Stat(ctx.Config.Changelog.File.Path)
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, Pipe{}.Publish(ctx))
+
+	bts, err := ioutil.ReadFile(ctx.Config.Changelog.File.Path)
+	require.NoError(t, err)
+	assert.Equal(t, keepAChangelogHeader+`
+## [1.1.0] - 2019-03-18
+
+### Added
+
+- feature 1
+
+### Fixed
+
+- bug 2
+
+### Contributors
+
+- @alice
+`, string(bts))
+}
+
+const existingChangelog = `# Changelog
+
+Some words.
+
+## [Unreleased]
+
+- not released yet
+
+## [1.0.0] - 2019-02-01
+
+### Added
+
+- first
+
+[1.0.0]: https://github.com/goreleaser/goreleaser/releases/tag/v1.0.0
+`
+
+func TestFilePrepended(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var ctx = fileContext(t, folder, false)
+	require.NoError(t, ioutil.WriteFile(ctx.Config.Changelog.File.Path, []byte(existingChangelog), 0644))
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.NoError(t, Pipe{}.Publish(ctx))
+
+	bts, err := ioutil.ReadFile(ctx.Config.Changelog.File.Path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Changelog
+
+Some words.
+
+## [Unreleased]
+
+- not released yet
+
+## [1.1.0] - 2019-03-18
+
+### Added
+
+- feature 1
+
+### Fixed
+
+- bug 2
+
+### Contributors
+
+- @alice
+
+## [1.0.0] - 2019-02-01
+
+### Added
+
+- first
+
+[1.0.0]: https://github.com/goreleaser/goreleaser/releases/tag/v1.0.0
+`, string(bts))
+}
+
+func TestFileDuplicatedVersion(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	for _, heading := range []string{"## [1.1.0] - 2019-03-17", "## 1.1.0", "## [v1.1.0]"} {
+		var ctx = fileContext(t, folder, false)
+		var content = existingChangelog + "\n" + heading + "\n"
+		require.NoError(t, ioutil.WriteFile(ctx.Config.Changelog.File.Path, []byte(content), 0644))
+		assert.EqualError(t, Pipe{}.Run(ctx), ctx.Config.Changelog.File.Path+" already has an entry for 1.1.0")
+
+		bts, err := ioutil.ReadFile(ctx.Config.Changelog.File.Path)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(bts))
+		require.NoError(t, os.RemoveAll(filepath.Join(folder, ".git")))
+	}
+}
+
+func TestFileCommitted(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	var ctx = fileContext(t, folder, true)
+	ctx.Config.Release.GitHub = config.Repo{Owner: "goreleaser", Name: "goreleaser"}
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, ioutil.WriteFile(ctx.Config.Changelog.File.Path, []byte(existingChangelog), 0644))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	// the local file is left untouched
+	var c = &DummyClient{}
+	require.NoError(t, doPublish(ctx, c))
+	bts, err := ioutil.ReadFile(ctx.Config.Changelog.File.Path)
+	require.NoError(t, err)
+	assert.Equal(t, existingChangelog, string(bts))
+	assert.True(t, c.CreatedFile)
+	assert.Equal(t, ctx.Config.Changelog.File.Path, c.Path)
+	assert.Equal(t, "Update "+ctx.Config.Changelog.File.Path+" for v1.1.0", c.Message)
+	assert.Equal(t, config.Repo{Owner: "goreleaser", Name: "goreleaser"}, c.Repo)
+	assert.Equal(t, "goreleaserbot", c.Author.Name)
+	assert.Contains(t, c.Content, "## [1.1.0] - 2019-03-18\n\n### Added\n\n- feature 1\n")
+	assert.Contains(t, c.Content, "## [1.0.0] - 2019-02-01")
+}
+
+func TestFilePublishSkipped(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	testlib.AssertSkipped(t, Pipe{}.Publish(ctx))
+}
+
+type DummyClient struct {
+	CreatedFile bool
+	Author      config.CommitAuthor
+	Repo        config.Repo
+	Content     string
+	Path        string
+	Message     string
+}
+
+func (c *DummyClient) CreateRelease(ctx *context.Context, body string) (releaseID int64, err error) {
+	return
+}
+
+func (c *DummyClient) MergedPullRequests(ctx *context.Context, repo config.Repo, from, to string) (prs []client.PullRequest, err error) {
+	return
+}
+
+func (c *DummyClient) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo config.Repo, content bytes.Buffer, path, msg string) (err error) {
+	c.CreatedFile = true
+	c.Author = commitAuthor
+	c.Repo = repo
+	c.Content = content.String()
+	c.Path = path
+	c.Message = msg
+	return
+}
+
+func (c *DummyClient) Upload(ctx *context.Context, releaseID int64, name string, file *os.File) (err error) {
+	return
+}
@@ -0,0 +1,53 @@
+package changelog
+
+import (
+	"sort"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// buildPullRequests returns the filtered and sorted pull requests merged
+// between the given refs, from the first commit if from is empty
+func buildPullRequests(ctx *context.Context, c client.Client, from, to string) ([]client.PullRequest, error) {
+	if from == "" {
+		first, err := git.Clean(git.Run("rev-list", "--max-parents=0", git.TagRef(to)))
+		if err != nil {
+			return nil, err
+		}
+		from = first
+	}
+	prs, err := c.MergedPullRequests(ctx, client.ReleaseRepo(ctx), from, to)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := excludeFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: prealloc
+	var result []client.PullRequest
+	for _, pr := range prs {
+		if !excluded(filters, pr.Title) {
+			result = append(result, pr)
+		}
+	}
+	if direction := ctx.Config.Changelog.Sort; direction != "" {
+		sort.SliceStable(result, func(i, j int) bool {
+			return less(direction, result[i].Title, result[j].Title)
+		})
+	}
+	return result, nil
+}
+
+func hasLabel(pr client.PullRequest, labels []string) bool {
+	for _, label := range labels {
+		for _, l := range pr.Labels {
+			if l == label {
+				return true
+			}
+		}
+	}
+	return false
+}
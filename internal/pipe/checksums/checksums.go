@@ -4,8 +4,11 @@ package checksums
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/apex/log"
 
@@ -27,6 +30,9 @@ func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Checksum.NameTemplate == "" {
 		ctx.Config.Checksum.NameTemplate = "{{ .ProjectName }}_{{ .Version }}_checksums.txt"
 	}
+	if ctx.Config.Checksum.Algorithm == "" {
+		ctx.Config.Checksum.Algorithm = "sha256"
+	}
 	return nil
 }
 
@@ -46,33 +52,76 @@ func (Pipe) Run(ctx *context.Context) (err error) {
 	}
 	defer file.Close() // nolint: errcheck
 
-	var g = semerrgroup.New(ctx.Parallelism)
-	for _, artifact := range ctx.Artifacts.Filter(
+	var artifacts = ctx.Artifacts.Filter(
 		artifact.Or(
 			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByType(artifact.UploadableSourceArchive),
 			artifact.ByType(artifact.UploadableBinary),
 			artifact.ByType(artifact.LinuxPackage),
 		),
-	).List() {
-		artifact := artifact
+	).List()
+	// sorted, so the checksums file is the same on every run
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Name < artifacts[j].Name
+	})
+	var lines = make([]string, len(artifacts))
+	var sidecars = make([]artifact.Artifact, len(artifacts))
+	var g = semerrgroup.New(ctx.Parallelism)
+	for i, a := range artifacts {
+		i, a := i, a
 		g.Go(func() error {
-			return checksums(file, artifact)
+			line, err := checksums(ctx, a)
+			lines[i] = line
+			if err != nil || !ctx.Config.Checksum.Sidecar {
+				return err
+			}
+			sidecars[i], err = sidecar(ctx, a, line)
+			return err
 		})
 	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	// added after all of them are written, in the same order as the lines
+	if ctx.Config.Checksum.Sidecar {
+		for _, a := range sidecars {
+			ctx.Artifacts.Add(a)
+		}
+	}
+	if _, err := file.WriteString(strings.Join(lines, "")); err != nil {
+		return err
+	}
 	ctx.Artifacts.Add(artifact.Artifact{
 		Type: artifact.Checksum,
 		Path: file.Name(),
 		Name: filename,
 	})
-	return g.Wait()
+	return nil
 }
 
-func checksums(file *os.File, artifact artifact.Artifact) error {
-	log.WithField("file", artifact.Name).Info("checksumming")
-	sha, err := artifact.Checksum()
+func checksums(ctx *context.Context, a artifact.Artifact) (string, error) {
+	log.WithField("file", a.Name).Info("checksumming")
+	sum, err := ctx.Artifacts.Checksum(a, ctx.Config.Checksum.Algorithm)
 	if err != nil {
-		return err
+		return "", err
+	}
+	return fmt.Sprintf("%v  %v\n", sum, a.Name), nil
+}
+
+// sidecar writes the checksum line of the artifact to its own file, which
+// carries the artifact so it can be filtered by the same ids
+func sidecar(ctx *context.Context, a artifact.Artifact, line string) (artifact.Artifact, error) {
+	var name = a.Name + "." + ctx.Config.Checksum.Algorithm
+	var path = filepath.Join(ctx.Config.Dist, name)
+	if err := ioutil.WriteFile(path, []byte(line), 0444); err != nil {
+		return artifact.Artifact{}, err
 	}
-	_, err = file.WriteString(fmt.Sprintf("%v  %v\n", sha, artifact.Name))
-	return err
+	return artifact.Artifact{
+		Type: artifact.ChecksumSidecar,
+		Path: path,
+		Name: name,
+		Extra: map[string]interface{}{
+			"Artifact": a,
+		},
+	}, nil
 }
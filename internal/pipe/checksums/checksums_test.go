@@ -29,6 +29,7 @@ func TestPipe(t *testing.T) {
 			ProjectName: binary,
 			Checksum: config.Checksum{
 				NameTemplate: "{{ .ProjectName }}_{{ .Env.FOO }}_checksums.txt",
+				Algorithm:    "sha256",
 			},
 		},
 	)
@@ -64,6 +65,7 @@ func TestPipeFileNotExist(t *testing.T) {
 			Dist: folder,
 			Checksum: config.Checksum{
 				NameTemplate: "checksums.txt",
+				Algorithm:    "sha256",
 			},
 		},
 	)
@@ -117,6 +119,7 @@ func TestPipeCouldNotOpenChecksumsTxt(t *testing.T) {
 			Dist: folder,
 			Checksum: config.Checksum{
 				NameTemplate: "checksums.txt",
+				Algorithm:    "sha256",
 			},
 		},
 	)
@@ -142,6 +145,7 @@ func TestDefault(t *testing.T) {
 		"{{ .ProjectName }}_{{ .Version }}_checksums.txt",
 		ctx.Config.Checksum.NameTemplate,
 	)
+	assert.Equal(t, "sha256", ctx.Config.Checksum.Algorithm)
 }
 
 func TestDefaultSet(t *testing.T) {
@@ -155,3 +159,85 @@ func TestDefaultSet(t *testing.T) {
 	assert.NoError(t, Pipe{}.Default(ctx))
 	assert.Equal(t, "checksums.txt", ctx.Config.Checksum.NameTemplate)
 }
+
+func TestPipeSortedAndSidecar(t *testing.T) {
+	folder, err := ioutil.TempDir("", "goreleasertest")
+	assert.NoError(t, err)
+	var ctx = context.New(
+		config.Project{
+			Dist: folder,
+			Checksum: config.Checksum{
+				NameTemplate: "checksums.txt",
+				Algorithm:    "md5",
+				Sidecar:      true,
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "1.2.3"
+	for _, name := range []string{"c.tar.gz", "a.tar.gz", "b.deb"} {
+		var file = filepath.Join(folder, name)
+		assert.NoError(t, ioutil.WriteFile(file, []byte(name), 0644))
+		ctx.Artifacts.Add(artifact.Artifact{
+			Name: name,
+			Path: file,
+			Type: artifact.UploadableArchive,
+			Extra: map[string]interface{}{
+				"ID": name[:1],
+			},
+		})
+	}
+	assert.NoError(t, Pipe{}.Run(ctx))
+
+	bts, err := ioutil.ReadFile(filepath.Join(folder, "checksums.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "273ebbf14b2c908291d81e9463dbe9b5  a.tar.gz\n"+
+		"692361e13d338c1881e9e74af948644f  b.deb\n"+
+		"1686e817208f950c1d7979e9fd773dd4  c.tar.gz\n", string(bts))
+
+	bts, err = ioutil.ReadFile(filepath.Join(folder, "b.deb.md5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "692361e13d338c1881e9e74af948644f  b.deb\n", string(bts))
+
+	var checksums []string
+	for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.Checksum)).List() {
+		checksums = append(checksums, a.Name)
+	}
+	assert.Equal(t, []string{"checksums.txt"}, checksums)
+
+	var sidecars []string
+	for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.ChecksumSidecar)).List() {
+		sidecars = append(sidecars, a.Name)
+	}
+	assert.Equal(t, []string{"a.tar.gz.md5", "b.deb.md5", "c.tar.gz.md5"}, sidecars)
+
+	// sidecars are selected by the ids of their artifacts
+	var selected = ctx.Artifacts.Filter(artifact.And(
+		artifact.ByType(artifact.ChecksumSidecar),
+		artifact.ByIDs("b"),
+	)).List()
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "b.deb.md5", selected[0].Name)
+}
+
+func TestPipeInvalidAlgorithm(t *testing.T) {
+	folder, err := ioutil.TempDir("", "goreleasertest")
+	assert.NoError(t, err)
+	var file = filepath.Join(folder, "binary")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("some string"), 0644))
+	var ctx = context.New(
+		config.Project{
+			Dist: folder,
+			Checksum: config.Checksum{
+				NameTemplate: "checksums.txt",
+				Algorithm:    "sha1024",
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "1.2.3"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name: "binary",
+		Path: file,
+		Type: artifact.UploadableBinary,
+	})
+	assert.EqualError(t, Pipe{}.Run(ctx), "invalid checksum algorithm: sha1024")
+}
@@ -1,13 +1,18 @@
 package defaults
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/goreleaser/pkg/jsonschema"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestDescription(t *testing.T) {
@@ -34,13 +39,13 @@ func TestFillBasicData(t *testing.T) {
 	assert.Contains(t, ctx.Config.Builds[0].Goos, "linux")
 	assert.Contains(t, ctx.Config.Builds[0].Goarch, "386")
 	assert.Contains(t, ctx.Config.Builds[0].Goarch, "amd64")
-	assert.Equal(t, "tar.gz", ctx.Config.Archive.Format)
+	assert.Equal(t, "tar.gz", ctx.Config.Archives[0].Format)
 	assert.Contains(t, ctx.Config.Brew.Install, "bin.install \"goreleaser\"")
 	assert.Empty(t, ctx.Config.Dockers)
 	assert.Equal(t, "https://github.com", ctx.Config.GitHubURLs.Download)
-	assert.NotEmpty(t, ctx.Config.Archive.NameTemplate)
+	assert.NotEmpty(t, ctx.Config.Archives[0].NameTemplate)
 	assert.NotEmpty(t, ctx.Config.Builds[0].Ldflags)
-	assert.NotEmpty(t, ctx.Config.Archive.Files)
+	assert.NotEmpty(t, ctx.Config.Archives[0].Files)
 	assert.NotEmpty(t, ctx.Config.Dist)
 }
 
@@ -63,8 +68,8 @@ func TestFillPartial(t *testing.T) {
 				},
 			},
 			Archive: config.Archive{
-				Files: []string{
-					"glob/*",
+				Files: []config.File{
+					{Source: "glob/*"},
 				},
 			},
 			Builds: []config.Build{
@@ -85,7 +90,7 @@ func TestFillPartial(t *testing.T) {
 		},
 	}
 	assert.NoError(t, Pipe{}.Run(ctx))
-	assert.Len(t, ctx.Config.Archive.Files, 1)
+	assert.Len(t, ctx.Config.Archives[0].Files, 1)
 	assert.Equal(t, `bin.install "testreleaser"`, ctx.Config.Brew.Install)
 	assert.NotEmpty(t, ctx.Config.Dockers[0].Binaries)
 	assert.NotEmpty(t, ctx.Config.Dockers[0].Goos)
@@ -95,3 +100,80 @@ func TestFillPartial(t *testing.T) {
 	assert.Equal(t, "disttt", ctx.Config.Dist)
 	assert.NotEqual(t, "https://github.com", ctx.Config.GitHubURLs.Download)
 }
+
+// TestSchemaDefaults checks the defaults of the JSON schema against the
+// values the defaulters set on an empty project, with one element in each
+// list so the defaults of the list items are set too. The deprecated single
+// build, archive and sign are moved into their lists, so they are skipped.
+func TestSchemaDefaults(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:goreleaser/goreleaser.git")
+
+	var ctx = context.New(config.Project{})
+	seedLists(reflect.ValueOf(&ctx.Config).Elem())
+	ctx.Config.S3[0].Bucket = "bucket"
+	ctx.Config.SingleBuild = config.Build{}
+	ctx.Config.Archive = config.Archive{}
+	ctx.Config.Sign = config.Sign{}
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	bts, err := yaml.Marshal(ctx.Config)
+	require.NoError(t, err)
+	var project map[interface{}]interface{}
+	require.NoError(t, yaml.Unmarshal(bts, &project))
+
+	var schema = jsonschema.Reflect(&config.Project{})
+	var definition = *schema.Definitions["Project"]
+	definition.Properties = map[string]*jsonschema.Schema{}
+	for name, prop := range schema.Definitions["Project"].Properties {
+		if name != "build" && name != "archive" && name != "sign" {
+			definition.Properties[name] = prop
+		}
+	}
+	var checked = checkDefaults(t, schema.Definitions, &definition, project, "")
+	assert.True(t, checked > 20, "only %d defaults were checked", checked)
+}
+
+func seedLists(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				seedLists(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 && v.Type().Elem().Kind() == reflect.Struct {
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+		for i := 0; i < v.Len(); i++ {
+			seedLists(v.Index(i))
+		}
+	}
+}
+
+func checkDefaults(t *testing.T, definitions map[string]*jsonschema.Schema, schema *jsonschema.Schema, value map[interface{}]interface{}, path string) int {
+	var checked int
+	for name, prop := range schema.Properties {
+		var v = value[name]
+		if prop.Default != nil {
+			checked++
+			assert.Equal(t, prop.Default, v, "default of %s%s", path, name)
+		}
+		if prop.Items != nil {
+			prop = prop.Items
+			if list, ok := v.([]interface{}); ok && len(list) > 0 {
+				v = list[0]
+			}
+		}
+		if prop.Ref == "" {
+			continue
+		}
+		var def = definitions[strings.TrimPrefix(prop.Ref, "#/definitions/")]
+		child, _ := v.(map[interface{}]interface{})
+		checked += checkDefaults(t, definitions, def, child, path+name+".")
+	}
+	return checked
+}
@@ -7,11 +7,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/deprecate"
+	"github.com/goreleaser/goreleaser/internal/paths"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/semerrgroup"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
@@ -36,8 +38,12 @@ func (Pipe) Default(ctx *context.Context) error {
 		var docker = &ctx.Config.Dockers[i]
 
 		if docker.Image != "" {
-			deprecate.Notice("docker.image")
-			deprecate.Notice("docker.tag_templates")
+			if err := deprecate.Notice(ctx, "docker.image"); err != nil {
+				return err
+			}
+			if err := deprecate.Notice(ctx, "docker.tag_templates"); err != nil {
+				return err
+			}
 
 			if len(docker.TagTemplates) == 0 {
 				docker.TagTemplates = []string{"{{ .Version }}"}
@@ -52,7 +58,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		}
 
 		if docker.Binary != "" {
-			deprecate.Notice("docker.binary")
+			if err := deprecate.Notice(ctx, "docker.binary"); err != nil {
+				return err
+			}
 			docker.Binaries = append(docker.Binaries, docker.Binary)
 		}
 
@@ -117,6 +125,7 @@ func doRun(ctx *context.Context) error {
 					artifact.ByGoarch(docker.Goarch),
 					artifact.ByGoarm(docker.Goarm),
 					artifact.ByType(artifact.Binary),
+					artifact.ByIDs(docker.IDs...),
 					func(a artifact.Artifact) bool {
 						for _, bin := range docker.Binaries {
 							if a.ExtraOr("Binary", "").(string) == bin {
@@ -166,6 +175,9 @@ func process(ctx *context.Context, docker config.Docker, bins []artifact.Artifac
 			return errors.Wrapf(err, "failed to link extra file '%s'", file)
 		}
 	}
+	if err := templateFiles(ctx, docker, bins[0], tmp); err != nil {
+		return err
+	}
 	for _, bin := range bins {
 		if err := os.Link(bin.Path, filepath.Join(tmp, filepath.Base(bin.Path))); err != nil {
 			return errors.Wrap(err, "failed to link binary")
@@ -193,6 +205,9 @@ func process(ctx *context.Context, docker config.Docker, bins []artifact.Artifac
 			Goarch: docker.Goarch,
 			Goos:   docker.Goos,
 			Goarm:  docker.Goarm,
+			Extra: map[string]interface{}{
+				"Builds": bins,
+			},
 		})
 	}
 	return nil
@@ -226,6 +241,38 @@ func processBuildFlagTemplates(ctx *context.Context, docker config.Docker) ([]st
 	return buildFlags, nil
 }
 
+// templateFiles renders the templated extra files into the build context
+func templateFiles(ctx *context.Context, docker config.Docker, binary artifact.Artifact, root string) error {
+	for _, f := range docker.TemplatedFiles {
+		var dst = f.Destination
+		if dst == "" {
+			dst = f.Source
+		}
+		dst, ok := paths.Relative(dst)
+		if !ok {
+			return errors.Errorf("failed to template extra file '%s': dst '%s' is outside of the build context", f.Source, dst)
+		}
+		content, err := tmpl.New(ctx).
+			WithArtifact(binary, map[string]string{}).
+			ApplyFile(f.Source)
+		if err != nil {
+			return errors.Wrapf(err, "failed to template extra file '%s'", f.Source)
+		}
+		var mode = f.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		var path = filepath.Join(root, dst)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.Wrapf(err, "failed to template extra file '%s'", f.Source)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), mode); err != nil {
+			return errors.Wrapf(err, "failed to template extra file '%s'", f.Source)
+		}
+	}
+	return nil
+}
+
 // walks the src, recreating dirs and hard-linking files
 func link(src, dest string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -283,6 +330,27 @@ func dockerPush(ctx *context.Context, image artifact.Artifact) error {
 	}
 	log.Debugf("docker push output: \n%s", string(out))
 	image.Type = artifact.DockerImage
+	var extra = map[string]interface{}{}
+	for k, v := range image.Extra {
+		extra[k] = v
+	}
+	if digest := pushedDigest(string(out)); digest != "" {
+		extra["Digest"] = digest
+	}
+	image.Extra = extra
 	ctx.Artifacts.Add(image)
 	return nil
 }
+
+// nolint: gochecknoglobals
+var digestRe = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+
+// pushedDigest returns the digest of the image from the docker push output,
+// or an empty string if it can't be found
+func pushedDigest(out string) string {
+	var match = digestRe.FindStringSubmatch(out)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
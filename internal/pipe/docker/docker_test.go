@@ -653,6 +653,68 @@ func TestDockerNotInPath(t *testing.T) {
 	assert.EqualError(t, Pipe{}.Run(ctx), ErrNoDocker.Error())
 }
 
+func TestRunPipeIDs(t *testing.T) {
+	folder, err := ioutil.TempDir("", "dockertest")
+	require.NoError(t, err)
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	var dockerfile = filepath.Join(folder, "Dockerfile")
+	require.NoError(t, ioutil.WriteFile(dockerfile, []byte("FROM scratch"), 0644))
+
+	// a fake docker, so only the selection of the binaries is tested
+	var bin = filepath.Join(folder, "bin")
+	require.NoError(t, os.Mkdir(bin, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	var path = os.Getenv("PATH")
+	defer func() {
+		assert.NoError(t, os.Setenv("PATH", path))
+	}()
+	require.NoError(t, os.Setenv("PATH", bin+string(os.PathListSeparator)+path))
+
+	var ctx = context.New(config.Project{
+		ProjectName: "mybin",
+		Dist:        dist,
+		Dockers: []config.Docker{
+			{
+				ImageTemplates: []string{"mybin:latest"},
+				Goos:           "linux",
+				Goarch:         "amd64",
+				Dockerfile:     dockerfile,
+				Binaries:       []string{"mybin"},
+				IDs:            []string{"foo"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	for _, id := range []string{"foo", "bar"} {
+		var binPath = filepath.Join(dist, id, "mybin")
+		require.NoError(t, os.Mkdir(filepath.Dir(binPath), 0755))
+		require.NoError(t, ioutil.WriteFile(binPath, []byte("fake"), 0755))
+		ctx.Artifacts.Add(artifact.Artifact{
+			Name:   "mybin",
+			Path:   binPath,
+			Goarch: "amd64",
+			Goos:   "linux",
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				"Binary": "mybin",
+				"ID":     id,
+			},
+		})
+	}
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	var images = ctx.Artifacts.Filter(artifact.ByType(artifact.PublishableDockerImage)).List()
+	require.Len(t, images, 1)
+	var bins = images[0].Extra["Builds"].([]artifact.Artifact)
+	require.Len(t, bins, 1)
+	require.Equal(t, "foo", bins[0].Extra["ID"])
+
+	// without ids, both binaries match
+	ctx.Config.Dockers[0].IDs = nil
+	require.EqualError(t, Pipe{}.Run(ctx), "2 binaries match docker definition: [mybin]: linux_amd64_, should be 1")
+}
+
 func TestDefault(t *testing.T) {
 	var ctx = &context.Context{
 		Config: config.Project{
@@ -875,3 +937,42 @@ func inode(file string) uint64 {
 	stat := fileInfo.Sys().(*syscall.Stat_t)
 	return stat.Ino
 }
+
+func TestTemplateFiles(t *testing.T) {
+	folder, err := ioutil.TempDir("", "dockertemplated")
+	require.NoError(t, err)
+	var src = filepath.Join(folder, "VERSION.tpl")
+	require.NoError(t, ioutil.WriteFile(src, []byte("{{ .ProjectName }} {{ .Version }} {{ .Arch }}"), 0644))
+	var ctx = context.New(config.Project{ProjectName: "mybin"})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Version = "1.0.0"
+	var docker = config.Docker{
+		TemplatedFiles: []config.TemplatedFile{
+			{Source: src, Destination: "etc/VERSION"},
+		},
+	}
+	var root = filepath.Join(folder, "root")
+	require.NoError(t, templateFiles(ctx, docker, artifact.Artifact{Goarch: "amd64"}, root))
+	bts, err := ioutil.ReadFile(filepath.Join(root, "etc", "VERSION"))
+	require.NoError(t, err)
+	require.Equal(t, "mybin 1.0.0 amd64", string(bts))
+
+	docker.TemplatedFiles[0].Destination = "etc/../../VERSION"
+	require.EqualError(t, templateFiles(ctx, docker, artifact.Artifact{}, root), "failed to template extra file '"+src+"': dst '../VERSION' is outside of the build context")
+
+	docker.TemplatedFiles[0].Destination = "/VERSION"
+	require.EqualError(t, templateFiles(ctx, docker, artifact.Artifact{}, root), "failed to template extra file '"+src+"': dst '/VERSION' is outside of the build context")
+
+	docker.TemplatedFiles[0].Source = src + ".nope"
+	docker.TemplatedFiles[0].Destination = ""
+	require.Error(t, templateFiles(ctx, docker, artifact.Artifact{}, root))
+}
+
+func TestPushedDigest(t *testing.T) {
+	var out = `The push refers to repository [docker.io/goreleaser/test_run_pipe]
+5216338b40a7: Layer already exists
+latest: digest: sha256:2d6bc93a1d49e1f8e1e7ae07ec66af1d2a36d29c32a70d1e8b1e1b7a1b0d1a3c size: 528
+`
+	require.Equal(t, "sha256:2d6bc93a1d49e1f8e1e7ae07ec66af1d2a36d29c32a70d1e8b1e1b7a1b0d1a3c", pushedDigest(out))
+	require.Empty(t, pushedDigest("denied: requested access to the resource is denied"))
+}
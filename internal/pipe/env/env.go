@@ -5,6 +5,7 @@ package env
 import (
 	"bufio"
 	"os"
+	"strings"
 
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -15,6 +16,9 @@ import (
 // ErrMissingToken indicates an error when GITHUB_TOKEN is missing in the environment
 var ErrMissingToken = errors.New("missing GITHUB_TOKEN")
 
+// ErrMissingGitLabToken indicates an error when GITLAB_TOKEN is missing in the environment
+var ErrMissingGitLabToken = errors.New("missing GITLAB_TOKEN")
+
 // Pipe for env
 type Pipe struct{}
 
@@ -28,13 +32,34 @@ func (Pipe) Default(ctx *context.Context) error {
 	if env.GitHubToken == "" {
 		env.GitHubToken = "~/.config/goreleaser/github_token"
 	}
+	if env.GitLabToken == "" {
+		env.GitLabToken = "~/.config/goreleaser/gitlab_token"
+	}
 	return nil
 }
 
 // Run the pipe
 func (Pipe) Run(ctx *context.Context) error {
-	token, err := loadEnv("GITHUB_TOKEN", ctx.Config.EnvFiles.GitHubToken)
+	var paths = map[string]string{
+		"github": ctx.Config.EnvFiles.GitHubToken,
+		"gitlab": ctx.Config.EnvFiles.GitLabToken,
+	}
+	var name, errMissing = "github", ErrMissingToken
+	if ctx.Config.Release.GitLab.Name != "" {
+		name, errMissing = "gitlab", ErrMissingGitLabToken
+	}
+	token, err := loadEnv(strings.ToUpper(name)+"_TOKEN", paths[name])
 	ctx.Token = token
+	// the changelog may list the pull requests of another provider
+	if source := ctx.Config.Changelog.Source; source == name {
+		ctx.ChangelogToken = token
+	} else if path, ok := paths[source]; ok {
+		changelogToken, err := loadEnv(strings.ToUpper(source)+"_TOKEN", path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s token", source)
+		}
+		ctx.ChangelogToken = changelogToken
+	}
 	if ctx.SkipPublish {
 		return pipe.ErrSkipPublishEnabled
 	}
@@ -42,9 +67,9 @@ func (Pipe) Run(ctx *context.Context) error {
 		return pipe.Skip("release pipe is disabled")
 	}
 	if ctx.Token == "" && err == nil {
-		return ErrMissingToken
+		return errMissing
 	}
-	return errors.Wrap(err, "failed to load github token")
+	return errors.Wrapf(err, "failed to load %s token", name)
 }
 
 func loadEnv(env, path string) (string, error) {
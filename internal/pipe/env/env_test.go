@@ -21,6 +21,7 @@ func TestDefault(t *testing.T) {
 		ctx := context.New(config.Project{})
 		assert.NoError(t, Pipe{}.Default(ctx))
 		assert.Equal(t, "~/.config/goreleaser/github_token", ctx.Config.EnvFiles.GitHubToken)
+		assert.Equal(t, "~/.config/goreleaser/gitlab_token", ctx.Config.EnvFiles.GitLabToken)
 	})
 	t.Run("custom config config", func(tt *testing.T) {
 		cfg := "what"
@@ -50,6 +51,71 @@ func TestInvalidEnv(t *testing.T) {
 	assert.Error(t, Pipe{}.Run(ctx))
 }
 
+func TestValidGitLabEnv(t *testing.T) {
+	assert.NoError(t, os.Unsetenv("GITHUB_TOKEN"))
+	assert.NoError(t, os.Setenv("GITLAB_TOKEN", "qwerty"))
+	defer os.Unsetenv("GITLAB_TOKEN") // nolint: errcheck
+	var ctx = &context.Context{
+		Config: config.Project{
+			Release: config.Release{
+				GitLab: config.Repo{Owner: "foo", Name: "bar"},
+			},
+		},
+	}
+	assert.NoError(t, Pipe{}.Run(ctx))
+	assert.Equal(t, "qwerty", ctx.Token)
+}
+
+func TestChangelogSourceEnv(t *testing.T) {
+	assert.NoError(t, os.Setenv("GITHUB_TOKEN", "asdf"))
+	assert.NoError(t, os.Setenv("GITLAB_TOKEN", "qwerty"))
+	defer os.Unsetenv("GITLAB_TOKEN") // nolint: errcheck
+	var ctx = &context.Context{
+		Config: config.Project{
+			Changelog: config.Changelog{Source: "gitlab"},
+		},
+	}
+	assert.NoError(t, Pipe{}.Run(ctx))
+	assert.Equal(t, "asdf", ctx.Token)
+	assert.Equal(t, "qwerty", ctx.ChangelogToken)
+
+	ctx.Config.Changelog.Source = "github"
+	assert.NoError(t, Pipe{}.Run(ctx))
+	assert.Equal(t, "asdf", ctx.ChangelogToken)
+}
+
+func TestInvalidChangelogSourceEnvFile(t *testing.T) {
+	assert.NoError(t, os.Setenv("GITHUB_TOKEN", "asdf"))
+	assert.NoError(t, os.Unsetenv("GITLAB_TOKEN"))
+	folder, err := ioutil.TempDir("", "token")
+	assert.NoError(t, err)
+	var ctx = &context.Context{
+		Config: config.Project{
+			Changelog: config.Changelog{Source: "gitlab"},
+			EnvFiles: config.EnvFiles{
+				GitLabToken: folder,
+			},
+		},
+	}
+	assert.EqualError(t, Pipe{}.Run(ctx), fmt.Sprintf("failed to load gitlab token: read %s: is a directory", folder))
+}
+
+func TestInvalidGitLabEnv(t *testing.T) {
+	assert.NoError(t, os.Setenv("GITHUB_TOKEN", "asdf"))
+	assert.NoError(t, os.Unsetenv("GITLAB_TOKEN"))
+	var ctx = &context.Context{
+		Config: config.Project{
+			Release: config.Release{
+				GitLab: config.Repo{Owner: "foo", Name: "bar"},
+			},
+			EnvFiles: config.EnvFiles{
+				GitLabToken: "/this/wont/exist",
+			},
+		},
+	}
+	assert.EqualError(t, Pipe{}.Run(ctx), ErrMissingGitLabToken.Error())
+}
+
 func TestEmptyFileEnv(t *testing.T) {
 	assert.NoError(t, os.Unsetenv("GITHUB_TOKEN"))
 	var ctx = &context.Context{
@@ -3,7 +3,9 @@ package git
 import (
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/deprecate"
@@ -26,7 +28,9 @@ func (Pipe) Run(ctx *context.Context) error {
 		return ErrNoGit
 	}
 	if ctx.Config.Git.ShortHash {
-		deprecate.Notice("git.short_hash")
+		if err := deprecate.Notice(ctx, "git.short_hash"); err != nil {
+			return err
+		}
 	}
 	info, err := getInfo(ctx)
 	if err != nil {
@@ -78,6 +82,10 @@ func getGitInfo(ctx *context.Context) (context.GitInfo, error) {
 	if ctx.Config.Git.ShortHash {
 		commit = short
 	}
+	date, err := getCommitDate()
+	if err != nil {
+		return context.GitInfo{}, errors.Wrap(err, "couldn't get commit date")
+	}
 	url, err := getURL()
 	if err != nil {
 		return context.GitInfo{}, errors.Wrap(err, "couldn't get remote URL")
@@ -88,15 +96,18 @@ func getGitInfo(ctx *context.Context) (context.GitInfo, error) {
 			Commit:      commit,
 			FullCommit:  full,
 			ShortCommit: short,
+			CommitDate:  date,
 			URL:         url,
 			CurrentTag:  "v0.0.0",
 		}, ErrNoTag
 	}
 	return context.GitInfo{
 		CurrentTag:  tag,
+		PreviousTag: git.PreviousTag(tag),
 		Commit:      commit,
 		FullCommit:  full,
 		ShortCommit: short,
+		CommitDate:  date,
 		URL:         url,
 	}, nil
 }
@@ -133,6 +144,21 @@ func getFullCommit() (string, error) {
 	return git.Clean(git.Run("show", "--format='%H'", "HEAD"))
 }
 
+func getCommitDate() (time.Time, error) {
+	ct, err := git.Clean(git.Run("show", "--format='%ct'", "HEAD"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ct == "" {
+		return time.Time{}, nil
+	}
+	i, err := strconv.ParseInt(ct, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(i, 0).UTC(), nil
+}
+
 func getTag() (string, error) {
 	return git.Clean(git.Run("describe", "--tags", "--abbrev=0"))
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -37,6 +38,24 @@ func TestSingleCommit(t *testing.T) {
 	}
 	assert.NoError(t, Pipe{}.Run(ctx))
 	assert.Equal(t, "v0.0.1", ctx.Git.CurrentTag)
+	assert.Empty(t, ctx.Git.PreviousTag)
+}
+
+func TestPreviousTag(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:foo/bar.git")
+	testlib.GitCommit(t, "commit1")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "commit2")
+	testlib.GitTag(t, "v0.0.2")
+	var ctx = &context.Context{
+		Config: config.Project{},
+	}
+	assert.NoError(t, Pipe{}.Run(ctx))
+	assert.Equal(t, "v0.0.2", ctx.Git.CurrentTag)
+	assert.Equal(t, "v0.0.1", ctx.Git.PreviousTag)
 }
 
 func TestNoRemote(t *testing.T) {
@@ -144,6 +163,7 @@ func TestValidState(t *testing.T) {
 	assert.NoError(t, Pipe{}.Run(ctx))
 	assert.Equal(t, "v0.0.2", ctx.Git.CurrentTag)
 	assert.Equal(t, "git@github.com:foo/bar.git", ctx.Git.URL)
+	assert.WithinDuration(t, time.Now(), ctx.Git.CommitDate, time.Minute)
 }
 
 func TestSnapshotNoTags(t *testing.T) {
@@ -2,12 +2,16 @@
 package nfpm
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/linux"
+	"github.com/goreleaser/goreleaser/internal/paths"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/semerrgroup"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
@@ -56,6 +60,7 @@ func doRun(ctx *context.Context) error {
 	var linuxBinaries = ctx.Artifacts.Filter(artifact.And(
 		artifact.ByType(artifact.Binary),
 		artifact.ByGoos("linux"),
+		artifact.ByIDs(ctx.Config.NFPM.IDs...),
 	)).GroupByPlatform()
 	var g = semerrgroup.New(ctx.Parallelism)
 	for _, format := range ctx.Config.NFPM.Formats {
@@ -108,6 +113,10 @@ func create(ctx *context.Context, format, arch string, binaries []artifact.Artif
 		log.WithField("src", src).WithField("dst", dst).Debug("adding binary to package")
 		files[src] = dst
 	}
+	var templated = filepath.Join(ctx.Config.Dist, "templated", name+"."+format)
+	if err := templateFiles(ctx, templated, binaries[0], overrided.Replacements, files); err != nil {
+		return err
+	}
 	log.WithField("files", files).Debug("all archive files")
 
 	var info = nfpm.Info{
@@ -169,6 +178,52 @@ func create(ctx *context.Context, format, arch string, binaries []artifact.Artif
 		Goos:   binaries[0].Goos,
 		Goarch: binaries[0].Goarch,
 		Goarm:  binaries[0].Goarm,
+		Extra: map[string]interface{}{
+			"Builds": binaries,
+		},
 	})
 	return nil
 }
+
+// templateFiles renders the templated files into the given folder, which is
+// specific to the package being created, and adds them to the given files
+func templateFiles(ctx *context.Context, folder string, binary artifact.Artifact, replacements map[string]string, files map[string]string) error {
+	if len(ctx.Config.NFPM.TemplatedFiles) == 0 {
+		return nil
+	}
+	// start from scratch, in case of a previous run with the same dist
+	if err := os.RemoveAll(folder); err != nil {
+		return errors.Wrap(err, "failed to clean templated files folder")
+	}
+	for i, f := range ctx.Config.NFPM.TemplatedFiles {
+		if f.Destination == "" {
+			return errors.Errorf("templated file %s has no dst", f.Source)
+		}
+		// package paths are absolute, so only check that they don't go up
+		if _, ok := paths.Relative(strings.TrimPrefix(f.Destination, "/")); !ok {
+			return errors.Errorf("templated file %s: dst %s is outside of the package", f.Source, f.Destination)
+		}
+		content, err := tmpl.New(ctx).
+			WithArtifact(binary, replacements).
+			ApplyFile(f.Source)
+		if err != nil {
+			return errors.Wrapf(err, "failed to template %s", f.Source)
+		}
+		var mode = f.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		var path = filepath.Join(folder, strconv.Itoa(i), filepath.Base(f.Destination))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(content), mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+		files[path] = f.Destination
+	}
+	return nil
+}
@@ -78,6 +78,7 @@ func TestRunPipe(t *testing.T) {
 			Maintainer:  "me@me",
 			Vendor:      "asdf",
 			Homepage:    "https://goreleaser.github.io",
+			IDs:         []string{"default"},
 			NFPMOverridables: config.NFPMOverridables{
 				NameTemplate: defaultNameTemplate,
 				Dependencies: []string{"make"},
@@ -114,14 +115,27 @@ func TestRunPipe(t *testing.T) {
 				Goarch: goarch,
 				Goos:   goos,
 				Type:   artifact.Binary,
+				Extra:  map[string]interface{}{"ID": "default"},
 			})
 		}
 	}
+	// not selected by the ids
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name:   "other",
+		Path:   binPath,
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra:  map[string]interface{}{"ID": "other"},
+	})
 	assert.NoError(t, Pipe{}.Run(ctx))
 	var packages = ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List()
 	assert.Len(t, packages, 4)
 	for _, pkg := range packages {
 		assert.Contains(t, pkg.Name, "mybin_1.0.0_Tux_", "linux should have been replaced by Tux")
+		for _, bin := range pkg.Extra["Builds"].([]artifact.Artifact) {
+			assert.Equal(t, "mybin", bin.Name)
+		}
 	}
 	assert.Len(t, ctx.Config.NFPM.Files, 1, "should not modify the config file list")
 }
@@ -252,3 +266,45 @@ func TestOverrides(t *testing.T) {
 	assert.Equal(t, "bar", ctx.Config.NFPM.Overrides["deb"].NameTemplate)
 	assert.Equal(t, "bar", merged.NameTemplate)
 }
+
+func TestTemplateFiles(t *testing.T) {
+	folder, err := ioutil.TempDir("", "nfpmtemplated")
+	assert.NoError(t, err)
+	var src = filepath.Join(folder, "config.yml.tpl")
+	assert.NoError(t, ioutil.WriteFile(src, []byte("version: {{ .Version }}\nos: {{ .Os }}"), 0644))
+	var ctx = context.New(config.Project{
+		Dist: folder,
+		NFPM: config.NFPM{
+			TemplatedFiles: []config.TemplatedFile{
+				{Source: src, Destination: "/etc/mybin/config.yml", Mode: 0600},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Version = "1.0.0"
+	var files = map[string]string{}
+	var templated = filepath.Join(folder, "templated", "mybin.deb")
+	// leftovers of a previous run are removed
+	assert.NoError(t, os.MkdirAll(templated, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(templated, "old"), []byte("old"), 0644))
+	assert.NoError(t, templateFiles(ctx, templated, artifact.Artifact{Goos: "linux"}, map[string]string{"linux": "Tux"}, files))
+	assert.Len(t, files, 1)
+	_, err = os.Stat(filepath.Join(templated, "old"))
+	assert.True(t, os.IsNotExist(err))
+	for path, dst := range files {
+		assert.Equal(t, filepath.Join(templated, "0", "config.yml"), path)
+		assert.Equal(t, "/etc/mybin/config.yml", dst)
+		bts, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 1.0.0\nos: Tux", string(bts))
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	ctx.Config.NFPM.TemplatedFiles[0].Destination = "../config.yml"
+	assert.EqualError(t, templateFiles(ctx, templated, artifact.Artifact{}, nil, files), "templated file "+src+": dst ../config.yml is outside of the package")
+
+	ctx.Config.NFPM.TemplatedFiles[0].Destination = ""
+	assert.EqualError(t, templateFiles(ctx, templated, artifact.Artifact{}, nil, files), "templated file "+src+" has no dst")
+}
@@ -158,6 +158,7 @@ func TestRunPipe_ModeArchive(t *testing.T) {
 				Mode:     "archive",
 				Target:   fmt.Sprintf("%s/example-repo-local/{{ .ProjectName }}/{{ .Version }}/", server.URL),
 				Username: "deployuser",
+				IDs:      []string{"foo"},
 			},
 		},
 	})
@@ -166,14 +167,23 @@ func TestRunPipe_ModeArchive(t *testing.T) {
 	}
 	ctx.Version = "1.0.0"
 	ctx.Artifacts.Add(artifact.Artifact{
-		Type: artifact.UploadableArchive,
-		Name: "bin.tar.gz",
-		Path: tarfile.Name(),
+		Type:  artifact.UploadableArchive,
+		Name:  "bin.tar.gz",
+		Path:  tarfile.Name(),
+		Extra: map[string]interface{}{"ID": "foo"},
+	})
+	ctx.Artifacts.Add(artifact.Artifact{
+		Type:  artifact.LinuxPackage,
+		Name:  "bin.deb",
+		Path:  debfile.Name(),
+		Extra: map[string]interface{}{"ID": "foo"},
 	})
+	// not selected by the ids, so it would fail if uploaded
 	ctx.Artifacts.Add(artifact.Artifact{
-		Type: artifact.LinuxPackage,
-		Name: "bin.deb",
-		Path: debfile.Name(),
+		Type:  artifact.UploadableArchive,
+		Name:  "other.tar.gz",
+		Path:  tarfile.Name(),
+		Extra: map[string]interface{}{"ID": "bar"},
 	})
 
 	var uploads sync.Map
@@ -2,10 +2,15 @@ package release
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/pkg/errors"
 )
 
 const bodyTemplateText = `{{ .ReleaseNotes }}
@@ -14,25 +19,192 @@ const bodyTemplateText = `{{ .ReleaseNotes }}
 
 ## Docker images
 {{ range $element := . }}
-- ` + "`docker pull {{ . -}}`" + `
+- ` + "`docker pull {{ .Name -}}`" + `
 {{- end -}}
 {{- end }}
+
+{{- with .Install }}
+
+## Install
+{{ range . }}
+{{ .Name }}:
+
+` + "```sh" + `
+{{ range .Commands }}{{ . }}
+{{ end }}` + "```" + `
+{{ end -}}
+{{- end }}
 `
 
+// dockerImage is a docker image pushed for the release
+type dockerImage struct {
+	Name   string
+	Digest string
+}
+
+// releaseArtifact is an artifact uploaded to the release
+type releaseArtifact struct {
+	Name     string
+	Checksum string
+	Size     int64
+	URL      string
+}
+
+// install holds the commands to install the release with a package manager
+type install struct {
+	Name     string
+	Commands []string
+}
+
 func describeBody(ctx *context.Context) (bytes.Buffer, error) {
 	var out bytes.Buffer
+	var cfg = ctx.Config.Release
+	var fields = tmpl.Fields{
+		"ReleaseNotes": ctx.ReleaseNotes,
+		"DockerImages": dockerImages(ctx),
+		"Install":      installs(ctx),
+		"CompareURL":   compareURL(ctx),
+	}
+	if cfg.Header != "" || cfg.Footer != "" || cfg.BodyTemplate != "" {
+		artifacts, err := releaseArtifacts(ctx)
+		if err != nil {
+			return out, err
+		}
+		fields["Artifacts"] = artifacts
+	}
+
+	header, err := describePart(ctx, ctx.ReleaseHeader, cfg.Header, fields)
+	if err != nil {
+		return out, errors.Wrap(err, "failed to apply release header")
+	}
+	var body bytes.Buffer
+	if cfg.BodyTemplate != "" {
+		s, err := tmpl.New(ctx).WithExtraFields(fields).Apply(cfg.BodyTemplate)
+		if err != nil {
+			return out, errors.Wrap(err, "failed to apply release body template")
+		}
+		body.WriteString(s)
+	} else {
+		var bodyTemplate = template.Must(template.New("release").Parse(bodyTemplateText))
+		if err := bodyTemplate.Execute(&body, fields); err != nil {
+			return out, err
+		}
+	}
+	footer, err := describePart(ctx, ctx.ReleaseFooter, cfg.Footer, fields)
+	if err != nil {
+		return out, errors.Wrap(err, "failed to apply release footer")
+	}
+
 	// nolint:prealloc
-	var dockers []string
+	var parts []string
+	for _, part := range []string{header, body.String(), footer} {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		parts = append(parts, strings.TrimRight(part, "\n"))
+	}
+	out.WriteString(strings.Join(parts, "\n\n") + "\n")
+	return out, nil
+}
+
+// describePart returns the given file contents as is, or applies the
+// template from the config if there are none
+func describePart(ctx *context.Context, contents, text string, fields tmpl.Fields) (string, error) {
+	if contents != "" || text == "" {
+		return contents, nil
+	}
+	return tmpl.New(ctx).WithExtraFields(fields).Apply(text)
+}
+
+func dockerImages(ctx *context.Context) []dockerImage {
+	// nolint:prealloc
+	var images []dockerImage
 	for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List() {
-		dockers = append(dockers, a.Name)
-	}
-	var bodyTemplate = template.Must(template.New("release").Parse(bodyTemplateText))
-	err := bodyTemplate.Execute(&out, struct {
-		ReleaseNotes string
-		DockerImages []string
-	}{
-		ReleaseNotes: ctx.ReleaseNotes,
-		DockerImages: dockers,
-	})
-	return out, err
+		images = append(images, dockerImage{
+			Name:   a.Name,
+			Digest: a.ExtraOr("Digest", "").(string),
+		})
+	}
+	return images
+}
+
+func releaseArtifacts(ctx *context.Context) ([]releaseArtifact, error) {
+	var algorithm = ctx.Config.Checksum.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	// nolint:prealloc
+	var result []releaseArtifact
+	for _, a := range uploadableArtifacts(ctx) {
+		sum, err := ctx.Artifacts.Checksum(a, algorithm)
+		if err != nil {
+			return nil, err
+		}
+		size, err := ctx.Artifacts.Size(a)
+		if err != nil {
+			return nil, err
+		}
+		url, err := tmpl.New(ctx).WithArtifact(a, map[string]string{}).Apply(client.ReleaseURLTemplate(ctx))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, releaseArtifact{
+			Name:     a.Name,
+			Checksum: algorithm + ":" + sum,
+			Size:     size,
+			URL:      url,
+		})
+	}
+	return result, nil
+}
+
+func installs(ctx *context.Context) []install {
+	// nolint:prealloc
+	var result []install
+	var repo = ctx.Config.Brew.GitHub
+	if client.IsGitLab(ctx) {
+		repo = ctx.Config.Brew.GitLab
+	}
+	if brew := ctx.Config.Brew; repo.Name != "" && !brew.SkipUpload {
+		var tap = repo.Owner + "/" + strings.TrimPrefix(repo.Name, "homebrew-")
+		var commands []string
+		if !strings.HasPrefix(repo.Name, "homebrew-") {
+			tap = repo.String()
+			commands = append(commands, fmt.Sprintf("brew tap %s %s", tap, repoURL(ctx, tap)))
+		}
+		result = append(result, install{
+			Name:     "Homebrew",
+			Commands: append(commands, fmt.Sprintf("brew install %s/%s", tap, brew.Name)),
+		})
+	}
+	if scoop := ctx.Config.Scoop; scoop.Bucket.Name != "" {
+		result = append(result, install{
+			Name: "Scoop",
+			Commands: []string{
+				fmt.Sprintf("scoop bucket add %s %s.git", scoop.Bucket.Name, repoURL(ctx, scoop.Bucket.String())),
+				fmt.Sprintf("scoop install %s", scoop.Name),
+			},
+		})
+	}
+	return result
+}
+
+func compareURL(ctx *context.Context) string {
+	if ctx.Git.PreviousTag == "" {
+		return ""
+	}
+	var page = "/compare/"
+	if client.IsGitLab(ctx) {
+		page = "/-/compare/"
+	}
+	return repoURL(ctx, client.ReleaseRepo(ctx).String()) +
+		page + ctx.Git.PreviousTag + "..." + ctx.Git.CurrentTag
+}
+
+func repoURL(ctx *context.Context, repo string) string {
+	var download = ctx.Config.GitHubURLs.Download
+	if client.IsGitLab(ctx) {
+		download = ctx.Config.GitLabURLs.Download
+	}
+	return strings.TrimSuffix(download, "/") + "/" + repo
 }
@@ -3,6 +3,7 @@ package release
 import (
 	"flag"
 	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -66,3 +67,156 @@ func TestDontEscapeHTML(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, out.String(), changelog)
 }
+
+func TestDescribeBodyWithInstall(t *testing.T) {
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			Download: "https://github.com",
+		},
+		Brew: config.Homebrew{
+			Name: "goreleaser",
+			GitHub: config.Repo{
+				Owner: "goreleaser",
+				Name:  "homebrew-tap",
+			},
+		},
+		Scoop: config.Scoop{
+			Name: "goreleaser",
+			Bucket: config.Repo{
+				Owner: "goreleaser",
+				Name:  "scoop-bucket",
+			},
+		},
+	})
+	ctx.ReleaseNotes = "feature1: description\nfeature2: other description"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name: "goreleaser/goreleaser:latest",
+		Type: artifact.DockerImage,
+	})
+	out, err := describeBody(ctx)
+	assert.NoError(t, err)
+
+	var golden = "testdata/release3.golden"
+	if *update {
+		_ = ioutil.WriteFile(golden, out.Bytes(), 0655)
+	}
+	bts, err := ioutil.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(bts), out.String())
+}
+
+func TestInstallBrewTap(t *testing.T) {
+	var ctx = context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			Download: "https://github.com",
+		},
+		Brew: config.Homebrew{
+			Name: "goreleaser",
+			GitHub: config.Repo{
+				Owner: "goreleaser",
+				Name:  "formulas",
+			},
+		},
+	})
+	assert.Equal(t, []install{
+		{
+			Name: "Homebrew",
+			Commands: []string{
+				"brew tap goreleaser/formulas https://github.com/goreleaser/formulas",
+				"brew install goreleaser/formulas/goreleaser",
+			},
+		},
+	}, installs(ctx))
+
+	ctx.Config.Brew.SkipUpload = true
+	assert.Empty(t, installs(ctx))
+}
+
+func TestDescribeBodyHeaderFooter(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Release: config.Release{
+			Header: "# Welcome to {{ .Tag }}",
+			Footer: "**Full Changelog**: {{ .CompareURL }}",
+		},
+		GitHubURLs: config.GitHubURLs{
+			Download: "https://github.com",
+		},
+	})
+	ctx.Config.Release.GitHub = config.Repo{Owner: "goreleaser", Name: "goreleaser"}
+	ctx.Git.CurrentTag = "v1.1.0"
+	ctx.Git.PreviousTag = "v1.0.0"
+	ctx.ReleaseNotes = "feature1: description"
+	out, err := describeBody(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Welcome to v1.1.0\n\nfeature1: description\n\n"+
+		"**Full Changelog**: https://github.com/goreleaser/goreleaser/compare/v1.0.0...v1.1.0\n", out.String())
+
+	// files provided on the command line win and are kept as is
+	ctx.ReleaseHeader = "header from {{ a file }}\n"
+	ctx.ReleaseFooter = "footer from a file\n"
+	out, err = describeBody(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "header from {{ a file }}\n\nfeature1: description\n\nfooter from a file\n", out.String())
+}
+
+func TestDescribeBodyInvalidHeaderFooter(t *testing.T) {
+	for name, release := range map[string]config.Release{
+		"header": {Header: "{{ .Nope }}"},
+		"footer": {Footer: "{{ .Nope }}"},
+		"body":   {BodyTemplate: "{{ .Nope }}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var ctx = context.New(config.Project{Release: release})
+			ctx.Git.CurrentTag = "v1.0.0"
+			_, err := describeBody(ctx)
+			assert.EqualError(t, err, `failed to apply release `+name+
+				map[string]string{"body": " template"}[name]+
+				`: template: tmpl:1:3: executing "tmpl" at <.Nope>: map has no entry for key "Nope"`)
+		})
+	}
+}
+
+func TestDescribeBodyTemplate(t *testing.T) {
+	var folder, err = ioutil.TempDir("", "goreleaserbody")
+	assert.NoError(t, err)
+	var path = filepath.Join(folder, "bin.tar.gz")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("fake\ttargz"), 0644))
+
+	var ctx = context.New(config.Project{
+		Release: config.Release{
+			BodyTemplate: `{{ .ReleaseNotes }}
+{{ range .Artifacts }}
+- [{{ .Name }}]({{ .URL }}) {{ .Checksum }} {{ .Size }}
+{{- end }}
+{{ range .DockerImages }}
+- {{ .Name }}@{{ .Digest }}
+{{- end }}`,
+		},
+		GitHubURLs: config.GitHubURLs{
+			Download: "https://github.com",
+		},
+	})
+	ctx.Config.Release.GitHub = config.Repo{Owner: "goreleaser", Name: "goreleaser"}
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.ReleaseNotes = "feature1: description"
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name: "bin.tar.gz",
+		Path: path,
+		Type: artifact.UploadableArchive,
+	})
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name: "goreleaser/goreleaser:latest",
+		Type: artifact.DockerImage,
+		Extra: map[string]interface{}{
+			"Digest": "sha256:abc",
+		},
+	})
+	out, err := describeBody(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, `feature1: description
+
+- [bin.tar.gz](https://github.com/goreleaser/goreleaser/releases/download/v1.0.0/bin.tar.gz) sha256:04a2e6b0c0dd5f50786db2a5fa288271e664ae262408f8429426cbec6ae38b46 10
+
+- goreleaser/goreleaser:latest@sha256:abc
+`, out.String())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/semerrgroup"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -29,13 +30,15 @@ func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Release.NameTemplate == "" {
 		ctx.Config.Release.NameTemplate = "{{.Tag}}"
 	}
-	if ctx.Config.Release.GitHub.Name == "" {
-		repo, err := remoteRepo()
+	var remote string
+	if ctx.Config.Release.GitHub.Name == "" && ctx.Config.Release.GitLab.Name == "" {
+		var err error
+		remote, err = git.RemoteURL()
 		if err != nil && !ctx.Snapshot {
 			return err
 		}
-		ctx.Config.Release.GitHub = repo
 	}
+	client.DefaultRepo(ctx, remote)
 
 	// Check if we have to check the git tag for an indicator to mark as pre release
 	switch ctx.Config.Release.Prerelease {
@@ -57,9 +60,9 @@ func (Pipe) Default(ctx *context.Context) error {
 	return nil
 }
 
-// Publish github release
+// Publish github or gitlab release
 func (Pipe) Publish(ctx *context.Context) error {
-	c, err := client.NewGitHub(ctx)
+	c, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
@@ -71,7 +74,7 @@ func doPublish(ctx *context.Context, c client.Client) error {
 		return pipe.Skip("release pipe is disabled")
 	}
 	log.WithField("tag", ctx.Git.CurrentTag).
-		WithField("repo", ctx.Config.Release.GitHub.String()).
+		WithField("repo", client.ReleaseRepo(ctx).String()).
 		Info("creating or updating release")
 	body, err := describeBody(ctx)
 	if err != nil {
@@ -82,15 +85,7 @@ func doPublish(ctx *context.Context, c client.Client) error {
 		return err
 	}
 	var g = semerrgroup.New(ctx.Parallelism)
-	for _, artifact := range ctx.Artifacts.Filter(
-		artifact.Or(
-			artifact.ByType(artifact.UploadableArchive),
-			artifact.ByType(artifact.UploadableBinary),
-			artifact.ByType(artifact.Checksum),
-			artifact.ByType(artifact.Signature),
-			artifact.ByType(artifact.LinuxPackage),
-		),
-	).List() {
+	for _, artifact := range uploadableArtifacts(ctx) {
 		artifact := artifact
 		g.Go(func() error {
 			var repeats uint
@@ -118,6 +113,25 @@ func doPublish(ctx *context.Context, c client.Client) error {
 	return g.Wait()
 }
 
+// uploadableArtifacts returns the artifacts that should be uploaded to the
+// release
+func uploadableArtifacts(ctx *context.Context) []artifact.Artifact {
+	return ctx.Artifacts.Filter(
+		artifact.And(
+			artifact.Or(
+				artifact.ByType(artifact.UploadableArchive),
+				artifact.ByType(artifact.UploadableSourceArchive),
+				artifact.ByType(artifact.UploadableBinary),
+				artifact.ByType(artifact.Checksum),
+				artifact.ByType(artifact.ChecksumSidecar),
+				artifact.ByType(artifact.Signature),
+				artifact.ByType(artifact.LinuxPackage),
+			),
+			artifact.ByIDs(ctx.Config.Release.IDs...),
+		),
+	).List()
+}
+
 func upload(ctx *context.Context, c client.Client, releaseID int64, artifact artifact.Artifact) error {
 	file, err := os.Open(artifact.Path)
 	if err != nil {
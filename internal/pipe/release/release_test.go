@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -56,6 +57,51 @@ func TestRunPipe(t *testing.T) {
 	assert.Contains(t, client.UploadedFileNames, "bin.tar.gz")
 }
 
+func TestRunPipeWithIDs(t *testing.T) {
+	folder, err := ioutil.TempDir("", "goreleasertest")
+	assert.NoError(t, err)
+	tarfile, err := os.Create(filepath.Join(folder, "bin.tar.gz"))
+	assert.NoError(t, err)
+	tarfile2, err := os.Create(filepath.Join(folder, "foo.tar.gz"))
+	assert.NoError(t, err)
+	var config = config.Project{
+		Dist: folder,
+		Release: config.Release{
+			GitHub: config.Repo{
+				Owner: "test",
+				Name:  "test",
+			},
+			IDs: []string{"bin"},
+		},
+	}
+	var ctx = context.New(config)
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(artifact.Artifact{
+		Type: artifact.UploadableArchive,
+		Name: "bin.tar.gz",
+		Path: tarfile.Name(),
+		Extra: map[string]interface{}{
+			"Builds": []artifact.Artifact{
+				{Extra: map[string]interface{}{"ID": "bin"}},
+			},
+		},
+	})
+	ctx.Artifacts.Add(artifact.Artifact{
+		Type: artifact.UploadableArchive,
+		Name: "foo.tar.gz",
+		Path: tarfile2.Name(),
+		Extra: map[string]interface{}{
+			"Builds": []artifact.Artifact{
+				{Extra: map[string]interface{}{"ID": "foo"}},
+			},
+		},
+	})
+	client := &DummyClient{}
+	assert.NoError(t, doPublish(ctx, client))
+	assert.True(t, client.CreatedRelease)
+	assert.Equal(t, []string{"bin.tar.gz"}, client.UploadedFileNames)
+}
+
 func TestRunPipeReleaseCreationFailed(t *testing.T) {
 	var config = config.Project{
 		Release: config.Release{
@@ -177,6 +223,69 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, "goreleaser", ctx.Config.Release.GitHub.Owner)
 }
 
+func TestDefaultGitLab(t *testing.T) {
+	for remote, urls := range map[string]config.GitLabURLs{
+		"git@gitlab.com:goreleaser/goreleaser.git": {
+			API:      "https://gitlab.com/api/v4",
+			Download: "https://gitlab.com",
+		},
+		"https://gitlab.example.com/goreleaser/goreleaser.git": {
+			API:      "https://gitlab.example.com/api/v4",
+			Download: "https://gitlab.example.com",
+		},
+	} {
+		t.Run(remote, func(t *testing.T) {
+			_, back := testlib.Mktmp(t)
+			defer back()
+			testlib.GitInit(t)
+			testlib.GitRemoteAdd(t, remote)
+
+			var ctx = context.New(config.Project{})
+			assert.NoError(t, Pipe{}.Default(ctx))
+			assert.Empty(t, ctx.Config.Release.GitHub.String())
+			assert.Equal(t, "goreleaser/goreleaser", ctx.Config.Release.GitLab.String())
+			assert.Equal(t, urls, ctx.Config.GitLabURLs)
+		})
+	}
+}
+
+func TestDefaultGitLabCustomHost(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@git.example.com:goreleaser/goreleaser.git")
+
+	var ctx = context.New(config.Project{
+		GitLabURLs: config.GitLabURLs{
+			Download: "https://git.example.com",
+		},
+	})
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Equal(t, "goreleaser/goreleaser", ctx.Config.Release.GitLab.String())
+	assert.Equal(t, "https://git.example.com/api/v4", ctx.Config.GitLabURLs.API)
+}
+
+func TestDefaultGitLabFilled(t *testing.T) {
+	_, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:goreleaser/goreleaser.git")
+
+	var ctx = context.New(config.Project{
+		Release: config.Release{
+			GitLab: config.Repo{
+				Name:  "foo",
+				Owner: "bar",
+			},
+		},
+	})
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Empty(t, ctx.Config.Release.GitHub.String())
+	assert.Equal(t, "bar/foo", ctx.Config.Release.GitLab.String())
+	assert.Equal(t, "https://gitlab.com", ctx.Config.GitLabURLs.Download)
+	assert.Equal(t, "https://gitlab.com/api/v4", ctx.Config.GitLabURLs.API)
+}
+
 func TestDefaultPreReleaseAuto(t *testing.T) {
 	_, back := testlib.Mktmp(t)
 	defer back()
@@ -318,6 +427,10 @@ func (client *DummyClient) CreateRelease(ctx *context.Context, body string) (rel
 	return
 }
 
+func (client *DummyClient) MergedPullRequests(ctx *context.Context, repo config.Repo, from, to string) (prs []client.PullRequest, err error) {
+	return
+}
+
 func (client *DummyClient) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo config.Repo, content bytes.Buffer, path, msg string) (err error) {
 	return
 }
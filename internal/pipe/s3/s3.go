@@ -78,12 +78,17 @@ func upload(ctx *context.Context, conf config.S3) error {
 
 	var g = semerrgroup.New(ctx.Parallelism)
 	for _, artifact := range ctx.Artifacts.Filter(
-		artifact.Or(
-			artifact.ByType(artifact.UploadableArchive),
-			artifact.ByType(artifact.UploadableBinary),
-			artifact.ByType(artifact.Checksum),
-			artifact.ByType(artifact.Signature),
-			artifact.ByType(artifact.LinuxPackage),
+		artifact.And(
+			artifact.Or(
+				artifact.ByType(artifact.UploadableArchive),
+				artifact.ByType(artifact.UploadableSourceArchive),
+				artifact.ByType(artifact.UploadableBinary),
+				artifact.ByType(artifact.Checksum),
+				artifact.ByType(artifact.ChecksumSidecar),
+				artifact.ByType(artifact.Signature),
+				artifact.ByType(artifact.LinuxPackage),
+			),
+			artifact.ByIDs(conf.IDs...),
 		),
 	).List() {
 		artifact := artifact
@@ -26,7 +26,7 @@ func (Pipe) String() string {
 
 // Publish scoop manifest
 func (Pipe) Publish(ctx *context.Context) error {
-	client, err := client.NewGitHub(ctx)
+	client, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
@@ -45,12 +45,7 @@ func (Pipe) Default(ctx *context.Context) error {
 		ctx.Config.Scoop.CommitAuthor.Email = "goreleaser@carlosbecker.com"
 	}
 	if ctx.Config.Scoop.URLTemplate == "" {
-		ctx.Config.Scoop.URLTemplate = fmt.Sprintf(
-			"%s/%s/%s/releases/download/{{ .Tag }}/{{ .ArtifactName }}",
-			ctx.Config.GitHubURLs.Download,
-			ctx.Config.Release.GitHub.Owner,
-			ctx.Config.Release.GitHub.Name,
-		)
+		ctx.Config.Scoop.URLTemplate = client.ReleaseURLTemplate(ctx)
 	}
 	return nil
 }
@@ -59,7 +54,7 @@ func doRun(ctx *context.Context, client client.Client) error {
 	if ctx.Config.Scoop.Bucket.Name == "" {
 		return pipe.Skip("scoop section is not configured")
 	}
-	if ctx.Config.Archive.Format == "binary" {
+	if isBinaryRelease(ctx) {
 		return pipe.Skip("archive format is binary")
 	}
 
@@ -67,6 +62,7 @@ func doRun(ctx *context.Context, client client.Client) error {
 		artifact.And(
 			artifact.ByGoos("windows"),
 			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByIDs(ctx.Config.Scoop.IDs...),
 		),
 	).List()
 	if len(archives) == 0 {
@@ -138,7 +134,7 @@ func buildManifest(ctx *context.Context, artifacts []artifact.Artifact) (bytes.B
 			return result, err
 		}
 
-		sum, err := artifact.Checksum()
+		sum, err := ctx.Artifacts.Checksum(artifact, "sha256")
 		if err != nil {
 			return result, err
 		}
@@ -166,3 +162,13 @@ func binaries(a artifact.Artifact) []string {
 	}
 	return bins
 }
+
+// isBinaryRelease returns true if all archives ship raw binaries
+func isBinaryRelease(ctx *context.Context) bool {
+	for _, archive := range ctx.Config.Archives {
+		if archive.Format != "binary" {
+			return false
+		}
+	}
+	return len(ctx.Config.Archives) > 0
+}
@@ -100,8 +100,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							GitHub: config.Repo{
@@ -143,8 +145,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							GitHub: config.Repo{
@@ -185,8 +189,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							GitHub: config.Repo{
@@ -227,8 +233,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							GitHub: config.Repo{
@@ -261,8 +269,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							GitHub: config.Repo{
@@ -304,8 +314,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "tar.gz",
+						Archives: []config.Archive{
+							{
+								Format: "tar.gz",
+							},
 						},
 						Release: config.Release{
 							Draft: true,
@@ -343,8 +355,10 @@ func Test_doRun(t *testing.T) {
 						},
 						Dist:        ".",
 						ProjectName: "run-pipe",
-						Archive: config.Archive{
-							Format: "binary",
+						Archives: []config.Archive{
+							{
+								Format: "binary",
+							},
 						},
 						Release: config.Release{
 							Draft: true,
@@ -404,8 +418,10 @@ func Test_buildManifest(t *testing.T) {
 					},
 					Dist:        ".",
 					ProjectName: "run-pipe",
-					Archive: config.Archive{
-						Format: "tar.gz",
+					Archives: []config.Archive{
+						{
+							Format: "tar.gz",
+						},
 					},
 					Release: config.Release{
 						GitHub: config.Repo{
@@ -442,8 +458,10 @@ func Test_buildManifest(t *testing.T) {
 					},
 					Dist:        ".",
 					ProjectName: "run-pipe",
-					Archive: config.Archive{
-						Format: "tar.gz",
+					Archives: []config.Archive{
+						{
+							Format: "tar.gz",
+						},
 					},
 					Release: config.Release{
 						GitHub: config.Repo{
@@ -534,6 +552,10 @@ func (client *DummyClient) CreateRelease(ctx *context.Context, body string) (rel
 	return
 }
 
+func (client *DummyClient) MergedPullRequests(ctx *context.Context, repo config.Repo, from, to string) (prs []client.PullRequest, err error) {
+	return
+}
+
 func (client *DummyClient) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo config.Repo, content bytes.Buffer, path, msg string) (err error) {
 	client.CreatedFile = true
 	bts, _ := ioutil.ReadAll(&content)
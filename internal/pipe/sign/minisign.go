@@ -0,0 +1,165 @@
+package sign
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// minisignSigner creates minisign signatures of the blake2b hash of the
+// files, which can be verified with `minisign -V`
+type minisignSigner struct {
+	id      [8]byte
+	key     ed25519.PrivateKey
+	version string
+}
+
+// minisign secret keys are made of the signature algorithm, the kdf
+// algorithm, the checksum algorithm, the kdf salt and limits and the
+// (maybe encrypted) key id, key and checksum
+const (
+	minisignKeyLen      = 2 + 2 + 2 + 32 + 8 + 8 + minisignKeynumLen
+	minisignKeynumLen   = 8 + ed25519.PrivateKeySize + blake2b.Size256
+	minisignUntrusted   = "untrusted comment: "
+	minisignTrusted     = "trusted comment: "
+	minisignSigComment  = "signature from goreleaser secret key"
+	minisignScryptR     = 8
+	minisignMinOpsLimit = 32768
+)
+
+// newMinisignSigner loads and decrypts the minisign secret key of the config
+func newMinisignSigner(ctx *context.Context, cfg config.Sign) (*minisignSigner, error) {
+	key, err := secret(ctx, cfg.Key, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load signing key")
+	}
+	if key == "" {
+		return nil, errors.New("no signing key configured, set either key or key_file")
+	}
+	bts, err := decodeMinisign(key)
+	if err != nil || len(bts) != minisignKeyLen {
+		return nil, errors.New("failed to read signing key: invalid minisign secret key")
+	}
+	if string(bts[0:2]) != "Ed" || string(bts[4:6]) != "B2" {
+		return nil, errors.New("failed to read signing key: unsupported minisign secret key algorithm")
+	}
+	var keynum = bts[54:]
+	switch kdf := string(bts[2:4]); kdf {
+	case "Sc":
+		passphrase, err := secret(ctx, cfg.Passphrase, cfg.PassphraseFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load signing key passphrase")
+		}
+		passphrase = strings.TrimRight(passphrase, "\r\n")
+		stream, err := minisignKDF(
+			[]byte(passphrase),
+			bts[6:38],
+			binary.LittleEndian.Uint64(bts[38:46]),
+			binary.LittleEndian.Uint64(bts[46:54]),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt signing key")
+		}
+		for i := range keynum {
+			keynum[i] ^= stream[i]
+		}
+	case "\x00\x00":
+	default:
+		return nil, fmt.Errorf("failed to read signing key: unsupported minisign kdf %q", kdf)
+	}
+
+	var s = &minisignSigner{
+		key:     ed25519.PrivateKey(keynum[8 : 8+ed25519.PrivateKeySize]),
+		version: ctx.Version,
+	}
+	copy(s.id[:], keynum[:8])
+	var checksum = blake2b.Sum256(append(append([]byte("Ed"), s.id[:]...), s.key...))
+	if !bytes.Equal(checksum[:], keynum[8+ed25519.PrivateKeySize:]) {
+		return nil, errors.New("failed to decrypt signing key: wrong passphrase")
+	}
+	return s, nil
+}
+
+// sign writes a minisign signature of the file at path to signature
+func (s *minisignSigner) sign(path, signature string) error {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+	hash, _ := blake2b.New512(nil)
+	if _, err := io.Copy(hash, file); err != nil {
+		return errors.Wrapf(err, "sign: failed to sign %s", path)
+	}
+
+	var sig = ed25519.Sign(s.key, hash.Sum(nil))
+	var trusted = fmt.Sprintf("version:%s\tfile:%s\thashed", s.version, filepath.Base(path))
+	var global = ed25519.Sign(s.key, append(append([]byte{}, sig...), trusted...))
+
+	var out bytes.Buffer
+	out.WriteString(minisignUntrusted + minisignSigComment + "\n")
+	out.WriteString(base64.StdEncoding.EncodeToString(append(append([]byte("ED"), s.id[:]...), sig...)) + "\n")
+	out.WriteString(minisignTrusted + trusted + "\n")
+	out.WriteString(base64.StdEncoding.EncodeToString(global) + "\n")
+	return ioutil.WriteFile(signature, out.Bytes(), 0644)
+}
+
+// decodeMinisign decodes the base64 content of a minisign key, skipping the
+// untrusted comment line if any
+func decodeMinisign(key string) ([]byte, error) {
+	var scanner = bufio.NewScanner(strings.NewReader(key))
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, minisignUntrusted) {
+			continue
+		}
+		return base64.StdEncoding.DecodeString(line)
+	}
+	return nil, errors.New("empty minisign key")
+}
+
+// minisignKDF derives the stream the secret key is xored with from the
+// passphrase, picking the scrypt parameters from the limits the same way
+// libsodium does
+func minisignKDF(passphrase, salt []byte, opslimit, memlimit uint64) ([]byte, error) {
+	if opslimit < minisignMinOpsLimit {
+		opslimit = minisignMinOpsLimit
+	}
+	var r uint64 = minisignScryptR
+	var p uint64 = 1
+	var maxN uint64
+	if opslimit < memlimit/32 {
+		maxN = opslimit / (r * 4)
+	} else {
+		maxN = memlimit / (r * 128)
+	}
+	var nLog2 uint
+	for nLog2 = 1; nLog2 < 63; nLog2++ {
+		if uint64(1)<<nLog2 > maxN/2 {
+			break
+		}
+	}
+	if opslimit >= memlimit/32 {
+		var maxrp = (opslimit / 4) / (uint64(1) << nLog2)
+		if maxrp > 0x3fffffff {
+			maxrp = 0x3fffffff
+		}
+		p = maxrp / r
+	}
+	return scrypt.Key(passphrase, salt, 1<<nLog2, int(r), int(p), minisignKeynumLen)
+}
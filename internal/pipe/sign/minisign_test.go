@@ -0,0 +1,163 @@
+package sign
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// newMinisignKey generates a minisign secret key, encrypted with the given
+// passphrase unless it is empty, and returns it with its public key and id
+func newMinisignKey(t *testing.T, passphrase string) (string, ed25519.PublicKey, []byte) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	var id = make([]byte, 8)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+
+	var checksum = blake2b.Sum256(append(append([]byte("Ed"), id...), private...))
+	var keynum = append(append(append([]byte{}, id...), private...), checksum[:]...)
+	var kdf = []byte{0, 0}
+	var salt = make([]byte, 32)
+	var limits = make([]byte, 16)
+	if passphrase != "" {
+		kdf = []byte("Sc")
+		_, err = rand.Read(salt)
+		require.NoError(t, err)
+		binary.LittleEndian.PutUint64(limits[0:8], 32768)
+		binary.LittleEndian.PutUint64(limits[8:16], 16777216)
+		stream, err := minisignKDF([]byte(passphrase), salt, 32768, 16777216)
+		require.NoError(t, err)
+		for i := range keynum {
+			keynum[i] ^= stream[i]
+		}
+	}
+
+	var key []byte
+	key = append(key, "Ed"...)
+	key = append(key, kdf...)
+	key = append(key, "B2"...)
+	key = append(key, salt...)
+	key = append(key, limits...)
+	key = append(key, keynum...)
+	return "untrusted comment: minisign encrypted secret key\n" +
+		base64.StdEncoding.EncodeToString(key) + "\n", public, id
+}
+
+// minisignVerify checks the minisign signature of the file like
+// `minisign -V` does and returns its trusted comment
+func minisignVerify(t *testing.T, public ed25519.PublicKey, id []byte, sig, file string) string {
+	bts, err := ioutil.ReadFile(sig)
+	require.NoError(t, err)
+	var lines = strings.Split(string(bts), "\n")
+	require.Len(t, lines, 5)
+	require.True(t, strings.HasPrefix(lines[0], "untrusted comment: "))
+	require.Equal(t, "", lines[4])
+
+	signature, err := base64.StdEncoding.DecodeString(lines[1])
+	require.NoError(t, err)
+	require.Len(t, signature, 2+8+ed25519.SignatureSize)
+	require.Equal(t, "ED", string(signature[:2]))
+	require.Equal(t, id, signature[2:10])
+
+	content, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	var hash = blake2b.Sum512(content)
+	require.True(t, ed25519.Verify(public, hash[:], signature[10:]))
+
+	require.True(t, strings.HasPrefix(lines[2], "trusted comment: "))
+	var trusted = strings.TrimPrefix(lines[2], "trusted comment: ")
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(public, append(signature[10:], trusted...), global))
+	return trusted
+}
+
+func TestSignMinisign(t *testing.T) {
+	for name, passphrase := range map[string]string{"encrypted": "secret", "unencrypted": ""} {
+		t.Run(name, func(t *testing.T) {
+			key, public, id := newMinisignKey(t, passphrase)
+			tmpdir, err := ioutil.TempDir("", "goreleaser")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpdir)
+
+			var file = filepath.Join(tmpdir, "app_1.0.0_linux_amd64.tar.gz")
+			require.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+			var ctx = context.New(config.Project{
+				Dist: tmpdir,
+				Sign: config.Sign{
+					Signer:     "minisign",
+					Artifacts:  "archive",
+					Key:        "{{ .Env.MINISIGN_KEY }}",
+					Passphrase: "{{ .Env.MINISIGN_PASSWORD }}",
+				},
+			})
+			ctx.Git.CurrentTag = "v1.0.0"
+			ctx.Version = "1.0.0"
+			ctx.Env["MINISIGN_KEY"] = key
+			ctx.Env["MINISIGN_PASSWORD"] = passphrase
+			ctx.Artifacts.Add(artifact.Artifact{
+				Name: "app_1.0.0_linux_amd64.tar.gz",
+				Path: file,
+				Type: artifact.UploadableArchive,
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.NoError(t, Pipe{}.Run(ctx))
+
+			var sigs = ctx.Artifacts.Filter(artifact.ByType(artifact.Signature)).List()
+			require.Len(t, sigs, 1)
+			require.Equal(t, "app_1.0.0_linux_amd64.tar.gz.minisig", sigs[0].Name)
+			require.Equal(t, file+".minisig", sigs[0].Path)
+			require.Equal(t,
+				"version:1.0.0\tfile:app_1.0.0_linux_amd64.tar.gz\thashed",
+				minisignVerify(t, public, id, sigs[0].Path, file),
+			)
+		})
+	}
+}
+
+func TestSignMinisignKeyFile(t *testing.T) {
+	key, _, _ := newMinisignKey(t, "secret")
+	tmpdir, err := ioutil.TempDir("", "goreleaser")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	var keyFile = filepath.Join(tmpdir, "minisign.key")
+	var passFile = filepath.Join(tmpdir, "password.txt")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(key), 0600))
+	require.NoError(t, ioutil.WriteFile(passFile, []byte("secret\n"), 0600))
+	signer, err := newMinisignSigner(context.New(config.Project{}), config.Sign{
+		KeyFile:        keyFile,
+		PassphraseFile: passFile,
+	})
+	require.NoError(t, err)
+	require.Len(t, signer.key, ed25519.PrivateKeySize)
+}
+
+func TestSignMinisignErrors(t *testing.T) {
+	key, _, _ := newMinisignKey(t, "secret")
+	var ctx = context.New(config.Project{})
+	ctx.Git.CurrentTag = "v1.0.0"
+	for cfg, eerr := range map[*config.Sign]string{
+		{}:                              "no signing key configured, set either key or key_file",
+		{KeyFile: "/nope/minisign.key"}: "failed to load signing key: open /nope/minisign.key: no such file or directory",
+		{Key: "nope"}:                   "failed to read signing key: invalid minisign secret key",
+		{Key: key, Passphrase: "wrong"}: "failed to decrypt signing key: wrong passphrase",
+		{Key: "untrusted comment: only a comment"}: "failed to read signing key: invalid minisign secret key",
+	} {
+		_, err := newMinisignSigner(ctx, *cfg)
+		require.EqualError(t, err, eerr)
+	}
+}
@@ -0,0 +1,149 @@
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// openpgpSigner creates detached OpenPGP signatures without the gpg binary
+type openpgpSigner struct {
+	key   *packet.PrivateKey
+	armor bool
+}
+
+// newOpenPGPSigner loads the armored private key of the config and decrypts
+// it, along with its subkeys, which might be the ones used to sign
+func newOpenPGPSigner(ctx *context.Context, cfg config.Sign) (*openpgpSigner, error) {
+	key, err := secret(ctx, cfg.Key, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load signing key")
+	}
+	if key == "" {
+		return nil, errors.New("no signing key configured, set either key or key_file")
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signing key")
+	}
+	if len(keyring) != 1 {
+		return nil, errors.Errorf("signing key must contain exactly one key, found %d", len(keyring))
+	}
+	var entity = keyring[0]
+	if entity.PrivateKey == nil {
+		return nil, errors.New("signing key has no private key")
+	}
+	passphrase, err := secret(ctx, cfg.Passphrase, cfg.PassphraseFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load signing key passphrase")
+	}
+	passphrase = strings.TrimRight(passphrase, "\r\n")
+	var keys = []*packet.PrivateKey{entity.PrivateKey}
+	for _, subkey := range entity.Subkeys {
+		keys = append(keys, subkey.PrivateKey)
+	}
+	for _, k := range keys {
+		if k == nil || !k.Encrypted {
+			continue
+		}
+		if err := k.Decrypt([]byte(passphrase)); err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt signing key")
+		}
+	}
+	signingKey, err := signingKey(entity, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &openpgpSigner{key: signingKey, armor: cfg.Armor}, nil
+}
+
+// signingKey returns the first subkey that can sign, like gpg does, falling
+// back to the primary key if it is allowed to sign
+func signingKey(entity *openpgp.Entity, now time.Time) (*packet.PrivateKey, error) {
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil &&
+			subkey.Sig.FlagsValid && subkey.Sig.FlagSign &&
+			subkey.PublicKey.PubKeyAlgo.CanSign() &&
+			!subkey.Sig.KeyExpired(now) {
+			return subkey.PrivateKey, nil
+		}
+	}
+	for _, identity := range entity.Identities {
+		if sig := identity.SelfSignature; !sig.FlagsValid || sig.FlagSign {
+			return entity.PrivateKey, nil
+		}
+	}
+	return nil, errors.New("signing key has no key that can sign")
+}
+
+// sign writes a detached signature of the file at path to signature
+func (s *openpgpSigner) sign(path, signature string) error {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+	// openpgp.DetachSign always signs with the primary key, so the signature
+	// is built here to be able to sign with a subkey
+	var sig = &packet.Signature{
+		SigType:      packet.SigTypeBinary,
+		PubKeyAlgo:   s.key.PubKeyAlgo,
+		Hash:         crypto.SHA256,
+		CreationTime: time.Now(),
+		IssuerKeyId:  &s.key.KeyId,
+	}
+	var h = sig.Hash.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+	if err := sig.Sign(h, s.key, nil); err != nil {
+		return errors.Wrapf(err, "sign: failed to sign %s", path)
+	}
+	var out bytes.Buffer
+	if s.armor {
+		err = armorSignature(&out, sig)
+	} else {
+		err = sig.Serialize(&out)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(signature, out.Bytes(), 0644)
+}
+
+func armorSignature(out io.Writer, sig *packet.Signature) error {
+	w, err := armor.Encode(out, openpgp.SignatureType, nil)
+	if err != nil {
+		return err
+	}
+	if err := sig.Serialize(w); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// secret returns the templated value, or the contents of the file if the
+// value is empty
+func secret(ctx *context.Context, value, file string) (string, error) {
+	if value != "" {
+		return tmpl.New(ctx).Apply(value)
+	}
+	if file == "" {
+		return "", nil
+	}
+	bts, err := ioutil.ReadFile(file) // #nosec
+	return string(bts), err
+}
@@ -0,0 +1,189 @@
+package sign
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// newTestKey generates a throwaway key, returning it and its armored
+// private and public keys
+func newTestKey(t *testing.T) (*openpgp.Entity, string, string) {
+	entity, err := openpgp.NewEntity("goreleaser", "test", "test@goreleaser.com", nil)
+	require.NoError(t, err)
+
+	var private bytes.Buffer
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	var public bytes.Buffer
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return entity, private.String(), public.String()
+}
+
+func TestSignOpenPGP(t *testing.T) {
+	entity, private, public := newTestKey(t)
+	for name, armored := range map[string]bool{"binary": false, "armored": true} {
+		t.Run(name, func(t *testing.T) {
+			tmpdir, err := ioutil.TempDir("", "goreleaser")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpdir)
+
+			var file = filepath.Join(tmpdir, "checksums.txt")
+			require.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+			var ctx = context.New(config.Project{
+				Dist: tmpdir,
+				Sign: config.Sign{
+					Signer:    "openpgp",
+					Artifacts: "checksum",
+					Key:       "{{ .Env.SIGNING_KEY }}",
+					Armor:     armored,
+				},
+			})
+			ctx.Git.CurrentTag = "v1.0.0"
+			ctx.Env["SIGNING_KEY"] = private
+			ctx.Artifacts.Add(artifact.Artifact{
+				Name: "checksums.txt",
+				Path: file,
+				Type: artifact.Checksum,
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.NoError(t, Pipe{}.Run(ctx))
+
+			var sigs = ctx.Artifacts.Filter(artifact.ByType(artifact.Signature)).List()
+			require.Len(t, sigs, 1)
+			require.Equal(t, "checksums.txt.sig", sigs[0].Name)
+
+			signed, err := os.Open(file)
+			require.NoError(t, err)
+			defer signed.Close()
+			sig, err := os.Open(sigs[0].Path)
+			require.NoError(t, err)
+			defer sig.Close()
+			var check = openpgp.CheckDetachedSignature
+			if armored {
+				check = openpgp.CheckArmoredDetachedSignature
+			}
+			signer, err := check(openpgp.EntityList{entity}, signed, sig)
+			require.NoError(t, err)
+			require.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+
+			gpgVerify(t, public, sigs[0].Path, file)
+		})
+	}
+}
+
+// gpgVerify checks the signature with gpg, using a keyring with just the
+// given public key
+func gpgVerify(t *testing.T, public, sig, file string) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not present in $PATH")
+	}
+	homedir, err := ioutil.TempDir("", "gpghome")
+	require.NoError(t, err)
+	defer os.RemoveAll(homedir)
+	var cmd = exec.Command("gpg", "--homedir", homedir, "--import")
+	cmd.Stdin = bytes.NewBufferString(public)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("gpg", "--homedir", homedir, "--verify", sig, file).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), "test@goreleaser.com")
+}
+
+func TestSignOpenPGPKeyFile(t *testing.T) {
+	_, private, _ := newTestKey(t)
+	tmpdir, err := ioutil.TempDir("", "goreleaser")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	var keyFile = filepath.Join(tmpdir, "key.asc")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(private), 0600))
+	var ctx = context.New(config.Project{})
+	signer, err := newOpenPGPSigner(ctx, config.Sign{KeyFile: keyFile, Armor: true})
+	require.NoError(t, err)
+	require.True(t, signer.armor)
+	require.NotNil(t, signer.key)
+}
+
+func TestSignOpenPGPEncryptedSubkey(t *testing.T) {
+	// the primary key can only certify, so it has to sign with the subkey
+	var keyFile = filepath.Join("testdata", "subkey.asc")
+	tmpdir, err := ioutil.TempDir("", "goreleaser")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	var file = filepath.Join(tmpdir, "checksums.txt")
+	require.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+
+	var ctx = context.New(config.Project{})
+	ctx.Git.CurrentTag = "v1.0.0"
+	_, err = newOpenPGPSigner(ctx, config.Sign{KeyFile: keyFile, Passphrase: "nope"})
+	require.EqualError(t, err, "failed to decrypt signing key: openpgp: invalid data: private key checksum failure")
+
+	signer, err := newOpenPGPSigner(ctx, config.Sign{KeyFile: keyFile, Passphrase: "secret"})
+	require.NoError(t, err)
+	require.NoError(t, signer.sign(file, file+".sig"))
+
+	bts, err := ioutil.ReadFile(keyFile)
+	require.NoError(t, err)
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(bts))
+	require.NoError(t, err)
+	signed, err := os.Open(file)
+	require.NoError(t, err)
+	defer signed.Close()
+	sig, err := os.Open(file + ".sig")
+	require.NoError(t, err)
+	defer sig.Close()
+	entity, err := openpgp.CheckDetachedSignature(keyring, signed, sig)
+	require.NoError(t, err)
+	require.Equal(t, keyring[0].PrimaryKey.KeyId, entity.PrimaryKey.KeyId)
+}
+
+func TestSignOpenPGPErrors(t *testing.T) {
+	first, _, public := newTestKey(t)
+	second, _, _ := newTestKey(t)
+	var many bytes.Buffer
+	w, err := armor.Encode(&many, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, first.SerializePrivate(w, nil))
+	require.NoError(t, second.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	var ctx = context.New(config.Project{})
+	ctx.Git.CurrentTag = "v1.0.0"
+	for cfg, eerr := range map[*config.Sign]string{
+		{}:                         "no signing key configured, set either key or key_file",
+		{KeyFile: "/nope/key.asc"}: "failed to load signing key: open /nope/key.asc: no such file or directory",
+		{Key: "not a key"}:         "failed to read signing key: openpgp: invalid argument: no armored data found",
+		{Key: public}:              "signing key has no private key",
+		{Key: many.String()}:       "signing key must contain exactly one key, found 2",
+	} {
+		_, err := newOpenPGPSigner(ctx, *cfg)
+		require.EqualError(t, err, eerr)
+	}
+}
+
+func TestSignInvalidSigner(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Sign: config.Sign{
+			Signer:    "nope",
+			Artifacts: "checksum",
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Run(ctx), "invalid signer: nope")
+}
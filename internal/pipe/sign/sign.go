@@ -1,17 +1,26 @@
 package sign
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/deprecate"
 	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
+// ErrSignAndSigns happens when both the deprecated sign and the signs
+// sections are set, as the sign section would be ignored
+var ErrSignAndSigns = errors.New("sign and signs can't be used together, move sign into signs")
+
 // Pipe for artifact signing.
 type Pipe struct{}
 
@@ -21,18 +30,45 @@ func (Pipe) String() string {
 
 // Default sets the Pipes defaults.
 func (Pipe) Default(ctx *context.Context) error {
-	cfg := &ctx.Config.Sign
-	if cfg.Cmd == "" {
-		cfg.Cmd = "gpg"
-	}
-	if cfg.Signature == "" {
-		cfg.Signature = "${artifact}.sig"
+	if !reflect.DeepEqual(ctx.Config.Sign, config.Sign{}) {
+		if len(ctx.Config.Signs) > 0 {
+			return ErrSignAndSigns
+		}
+		if err := deprecate.Notice(ctx, "sign"); err != nil {
+			return err
+		}
 	}
-	if len(cfg.Args) == 0 {
-		cfg.Args = []string{"--output", "$signature", "--detach-sig", "$artifact"}
+	if len(ctx.Config.Signs) == 0 {
+		ctx.Config.Signs = append(ctx.Config.Signs, ctx.Config.Sign)
 	}
-	if cfg.Artifacts == "" {
-		cfg.Artifacts = "none"
+	var ids = map[string]bool{}
+	for i := range ctx.Config.Signs {
+		cfg := &ctx.Config.Signs[i]
+		if cfg.ID == "" {
+			cfg.ID = "default"
+		}
+		if ids[cfg.ID] {
+			return fmt.Errorf("found 2 signs with the ID '%s', please fix your config", cfg.ID)
+		}
+		ids[cfg.ID] = true
+		if cfg.Signer == "" {
+			cfg.Signer = "cmd"
+		}
+		if cfg.Cmd == "" {
+			cfg.Cmd = "gpg"
+		}
+		if cfg.Signature == "" && cfg.Signer == "minisign" {
+			cfg.Signature = "${artifact}.minisig"
+		}
+		if cfg.Signature == "" {
+			cfg.Signature = "${artifact}.sig"
+		}
+		if len(cfg.Args) == 0 {
+			cfg.Args = []string{"--output", "$signature", "--detach-sig", "$artifact"}
+		}
+		if cfg.Artifacts == "" {
+			cfg.Artifacts = "none"
+		}
 	}
 	return nil
 }
@@ -43,52 +79,141 @@ func (Pipe) Run(ctx *context.Context) error {
 		return pipe.ErrSkipSignEnabled
 	}
 
-	switch ctx.Config.Sign.Artifacts {
+	var signed bool
+	for _, cfg := range ctx.Config.Signs {
+		filter, err := artifactsFilter(cfg)
+		if err != nil {
+			return err
+		}
+		if filter == nil {
+			continue
+		}
+		signed = true
+		if err := sign(ctx, cfg, ctx.Artifacts.Filter(filter).List()); err != nil {
+			return err
+		}
+	}
+	if !signed {
+		return pipe.ErrSkipSignEnabled
+	}
+	return nil
+}
+
+// artifactsFilter returns the filter of the artifacts the given config
+// signs, or nil if it signs none
+func artifactsFilter(cfg config.Sign) (artifact.Filter, error) {
+	var filters []artifact.Filter
+	switch cfg.Artifacts {
 	case "checksum":
-		return sign(ctx, ctx.Artifacts.Filter(artifact.ByType(artifact.Checksum)).List())
+		filters = append(filters, artifact.ByType(artifact.Checksum))
+	case "source":
+		filters = append(filters, artifact.ByType(artifact.UploadableSourceArchive))
+	case "archive":
+		filters = append(filters, artifact.ByType(artifact.UploadableArchive))
+	case "binary":
+		filters = append(filters, artifact.ByType(artifact.UploadableBinary))
+	case "package":
+		filters = append(filters, artifact.ByType(artifact.LinuxPackage))
 	case "all":
-		return sign(ctx, ctx.Artifacts.Filter(
-			artifact.Or(
-				artifact.ByType(artifact.UploadableArchive),
-				artifact.ByType(artifact.UploadableBinary),
-				artifact.ByType(artifact.Checksum),
-				artifact.ByType(artifact.LinuxPackage),
-			)).List())
+		filters = append(filters,
+			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByType(artifact.UploadableSourceArchive),
+			artifact.ByType(artifact.UploadableBinary),
+			artifact.ByType(artifact.Checksum),
+			artifact.ByType(artifact.LinuxPackage),
+		)
 	case "none":
-		return pipe.ErrSkipSignEnabled
+		return nil, nil
 	default:
-		return fmt.Errorf("invalid list of artifacts to sign: %s", ctx.Config.Sign.Artifacts)
+		return nil, fmt.Errorf("invalid list of artifacts to sign: %s", cfg.Artifacts)
+	}
+	var filter = artifact.And(
+		artifact.Or(filters...),
+		artifact.ByIDs(cfg.IDs...),
+	)
+	if cfg.Glob == "" {
+		return filter, nil
 	}
+	if _, err := filepath.Match(cfg.Glob, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob to select artifacts to sign: %s", cfg.Glob)
+	}
+	return artifact.And(filter, func(a artifact.Artifact) bool {
+		match, _ := filepath.Match(cfg.Glob, a.Name)
+		return match
+	}), nil
 }
 
-func sign(ctx *context.Context, artifacts []artifact.Artifact) error {
-	// nolint:prealloc
-	var sigs []string
-	for _, a := range artifacts {
-		sig, err := signone(ctx, a)
-		if err != nil {
-			return err
-		}
-		sigs = append(sigs, sig)
+// signer signs files natively, without running a command
+type signer interface {
+	sign(path, signature string) error
+}
+
+// newSigner returns the native signer of the config, or nil if it runs a
+// command
+func newSigner(ctx *context.Context, cfg config.Sign) (signer, error) {
+	switch cfg.Signer {
+	case "openpgp":
+		return newOpenPGPSigner(ctx, cfg)
+	case "minisign":
+		return newMinisignSigner(ctx, cfg)
+	case "cmd":
+		return nil, nil
 	}
-	for _, sig := range sigs {
-		ctx.Artifacts.Add(artifact.Artifact{
-			Type: artifact.Signature,
-			Name: sig,
-			Path: filepath.Join(ctx.Config.Dist, sig),
+	return nil, fmt.Errorf("invalid signer: %s", cfg.Signer)
+}
+
+func sign(ctx *context.Context, cfg config.Sign, artifacts []artifact.Artifact) error {
+	native, err := newSigner(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	var sigs = make([]artifact.Artifact, len(artifacts))
+	var g = semerrgroup.New(ctx.Parallelism)
+	for i, a := range artifacts {
+		i, a := i, a
+		g.Go(func() error {
+			sig, err := signone(ctx, cfg, native, a)
+			if err != nil {
+				return err
+			}
+			sigs[i] = artifact.Artifact{
+				Type: artifact.Signature,
+				Name: sig,
+				Path: filepath.Join(ctx.Config.Dist, sig),
+				Extra: map[string]interface{}{
+					"ID":       a.ExtraOr("ID", ""),
+					"Builds":   a.ExtraOr("Builds", []artifact.Artifact{}),
+					"Checksum": a.Type == artifact.Checksum,
+					"Source":   a.Type == artifact.UploadableSourceArchive,
+					"Sign":     cfg.ID,
+				},
+			}
+			return nil
 		})
 	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	for _, sig := range sigs {
+		ctx.Artifacts.Add(sig)
+	}
 	return nil
 }
 
-func signone(ctx *context.Context, artifact artifact.Artifact) (string, error) {
-	cfg := ctx.Config.Sign
-
+func signone(ctx *context.Context, cfg config.Sign, native signer, artifact artifact.Artifact) (string, error) {
 	env := map[string]string{
 		"artifact": artifact.Path,
 	}
 	env["signature"] = expand(cfg.Signature, env)
 
+	if native != nil {
+		log.WithField("artifact", artifact.Name).WithField("signer", cfg.Signer).Debug("signing")
+		if err := native.sign(artifact.Path, env["signature"]); err != nil {
+			return "", err
+		}
+		return filepath.Base(env["signature"]), nil
+	}
+
 	// nolint:prealloc
 	var args []string
 	for _, a := range cfg.Args {
@@ -100,6 +225,10 @@ func signone(ctx *context.Context, artifact artifact.Artifact) (string, error) {
 	// tells the scanner to ignore this.
 	// #nosec
 	cmd := exec.CommandContext(ctx, cfg.Cmd, args...)
+	cmd.Env = os.Environ()
+	for _, e := range cfg.Env {
+		cmd.Env = append(cmd.Env, expand(e, env))
+	}
 	log.WithField("cmd", cmd.Args).Debug("running")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
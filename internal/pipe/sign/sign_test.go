@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,11 +40,57 @@ func TestDescription(t *testing.T) {
 
 func TestSignDefault(t *testing.T) {
 	ctx := &context.Context{}
-	Pipe{}.Default(ctx)
-	assert.Equal(t, ctx.Config.Sign.Cmd, "gpg")
-	assert.Equal(t, ctx.Config.Sign.Signature, "${artifact}.sig")
-	assert.Equal(t, ctx.Config.Sign.Args, []string{"--output", "$signature", "--detach-sig", "$artifact"})
-	assert.Equal(t, ctx.Config.Sign.Artifacts, "none")
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Len(t, ctx.Config.Signs, 1)
+	assert.Equal(t, ctx.Config.Signs[0].ID, "default")
+	assert.Equal(t, ctx.Config.Signs[0].Signer, "cmd")
+	assert.Equal(t, ctx.Config.Signs[0].Cmd, "gpg")
+	assert.Equal(t, ctx.Config.Signs[0].Signature, "${artifact}.sig")
+	assert.Equal(t, ctx.Config.Signs[0].Args, []string{"--output", "$signature", "--detach-sig", "$artifact"})
+	assert.Equal(t, ctx.Config.Signs[0].Artifacts, "none")
+}
+
+func TestSignDefaultList(t *testing.T) {
+	ctx := &context.Context{}
+	ctx.Config.Signs = []config.Sign{
+		{Artifacts: "checksum"},
+		{ID: "packages", Artifacts: "package", Cmd: "cosign"},
+	}
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Len(t, ctx.Config.Signs, 2)
+	assert.Equal(t, ctx.Config.Signs[0].ID, "default")
+	assert.Equal(t, ctx.Config.Signs[0].Cmd, "gpg")
+	assert.Equal(t, ctx.Config.Signs[1].ID, "packages")
+	assert.Equal(t, ctx.Config.Signs[1].Cmd, "cosign")
+	assert.Equal(t, ctx.Config.Signs[1].Signature, "${artifact}.sig")
+}
+
+func TestSignDefaultDuplicatedID(t *testing.T) {
+	ctx := &context.Context{}
+	ctx.Config.Signs = []config.Sign{
+		{ID: "a"},
+		{ID: "a"},
+	}
+	assert.EqualError(t, Pipe{}.Default(ctx), "found 2 signs with the ID 'a', please fix your config")
+}
+
+func TestSignDefaultSignAndSigns(t *testing.T) {
+	ctx := &context.Context{}
+	ctx.Config.Sign = config.Sign{Artifacts: "checksum"}
+	ctx.Config.Signs = []config.Sign{{Artifacts: "package"}}
+	assert.EqualError(t, Pipe{}.Default(ctx), ErrSignAndSigns.Error())
+}
+
+func TestSignDefaultDeprecated(t *testing.T) {
+	ctx := &context.Context{}
+	ctx.Config.Sign = config.Sign{Artifacts: "checksum"}
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.Len(t, ctx.Config.Signs, 1)
+	assert.Equal(t, ctx.Config.Signs[0].Artifacts, "checksum")
+
+	ctx = &context.Context{Strict: true}
+	ctx.Config.Sign = config.Sign{Artifacts: "checksum"}
+	assert.EqualError(t, Pipe{}.Default(ctx), "`sign` is deprecated and strict mode is enabled, check https://goreleaser.com/deprecations#sign for more info")
 }
 
 func TestSignDisabled(t *testing.T) {
@@ -63,6 +110,7 @@ func TestSignSkipped(t *testing.T) {
 func TestSignInvalidArtifacts(t *testing.T) {
 	ctx := &context.Context{}
 	ctx.Config.Sign.Artifacts = "foo"
+	assert.NoError(t, Pipe{}.Default(ctx))
 	err := Pipe{}.Run(ctx)
 	assert.EqualError(t, err, "invalid list of artifacts to sign: foo")
 }
@@ -91,6 +139,27 @@ func TestSignArtifacts(t *testing.T) {
 			),
 			signatures: []string{"checksum.sig"},
 		},
+		{
+			desc: "sign archives matching a glob",
+			ctx: context.New(
+				config.Project{
+					Sign: config.Sign{Artifacts: "archive", Glob: "*2"},
+				},
+			),
+			signatures: []string{"artifact2.sig"},
+		},
+		{
+			desc: "sign with multiple configs",
+			ctx: context.New(
+				config.Project{
+					Signs: []config.Sign{
+						{ID: "checksums", Artifacts: "checksum"},
+						{ID: "archives", Artifacts: "archive", Signature: "${artifact}.asc", Args: []string{"--armor", "--output", "$signature", "--detach-sig", "$artifact"}},
+					},
+				},
+			),
+			signatures: []string{"checksum.sig", "artifact1.asc", "artifact2.asc"},
+		},
 	}
 
 	for _, test := range tests {
@@ -135,7 +204,9 @@ func testSign(t *testing.T, ctx *context.Context, signatures []string) {
 	// configure the pipeline
 	// make sure we are using the test keyring
 	assert.NoError(t, Pipe{}.Default(ctx))
-	ctx.Config.Sign.Args = append([]string{"--homedir", keyring}, ctx.Config.Sign.Args...)
+	for i := range ctx.Config.Signs {
+		ctx.Config.Signs[i].Args = append([]string{"--homedir", keyring}, ctx.Config.Signs[i].Args...)
+	}
 
 	// run the pipeline
 	assert.NoError(t, Pipe{}.Run(ctx))
@@ -165,7 +236,7 @@ func testSign(t *testing.T, ctx *context.Context, signatures []string) {
 }
 
 func verifySignature(t *testing.T, ctx *context.Context, sig string) {
-	artifact := sig[:len(sig)-len(".sig")]
+	artifact := strings.TrimSuffix(sig, filepath.Ext(sig))
 
 	// verify signature was made with key for usesr 'nopass'
 	cmd := exec.Command("gpg", "--homedir", keyring, "--verify", filepath.Join(ctx.Config.Dist, sig), filepath.Join(ctx.Config.Dist, artifact))
@@ -180,3 +251,34 @@ func verifySignature(t *testing.T, ctx *context.Context, sig string) {
 		t.Fatalf("signature is not from %s", user)
 	}
 }
+
+func TestSignEnv(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "goreleaser")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	var file = filepath.Join(tmpdir, "artifact")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+	var ctx = context.New(config.Project{
+		Dist: tmpdir,
+		Signs: []config.Sign{
+			{
+				Cmd:       "sh",
+				Args:      []string{"-c", "printenv SIGN_TEST > $signature"},
+				Env:       []string{"SIGN_TEST=signature of $artifact"},
+				Artifacts: "binary",
+			},
+		},
+	})
+	ctx.Artifacts.Add(artifact.Artifact{
+		Name: "artifact",
+		Path: file,
+		Type: artifact.UploadableBinary,
+	})
+	assert.NoError(t, Pipe{}.Default(ctx))
+	assert.NoError(t, Pipe{}.Run(ctx))
+
+	bts, err := ioutil.ReadFile(file + ".sig")
+	assert.NoError(t, err)
+	assert.Equal(t, "signature of "+file+"\n", string(bts))
+}
@@ -87,6 +87,7 @@ func (Pipe) Run(ctx *context.Context) error {
 		artifact.And(
 			artifact.ByGoos("linux"),
 			artifact.ByType(artifact.Binary),
+			artifact.ByIDs(ctx.Config.Snapcraft.IDs...),
 		),
 	).GroupByPlatform() {
 		arch := linux.Arch(platform)
@@ -211,6 +212,9 @@ func create(ctx *context.Context, arch string, binaries []artifact.Artifact) err
 		Goos:   binaries[0].Goos,
 		Goarch: binaries[0].Goarch,
 		Goarm:  binaries[0].Goarm,
+		Extra: map[string]interface{}{
+			"Builds": binaries,
+		},
 	})
 	return nil
 }
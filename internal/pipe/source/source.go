@@ -0,0 +1,177 @@
+// Package source provides a Pipe that archives the source code of the
+// release commit, optionally with its vendored dependencies.
+package source
+
+import (
+	stdtar "archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/apex/log"
+	zglob "github.com/mattn/go-zglob"
+	"github.com/pkg/errors"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/archive/tar"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Pipe for source archives
+type Pipe struct{}
+
+func (Pipe) String() string {
+	return "source archive"
+}
+
+// Default sets the pipe defaults
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Source.NameTemplate == "" {
+		ctx.Config.Source.NameTemplate = "{{ .ProjectName }}_{{ .Version }}_source"
+	}
+	return nil
+}
+
+// Run the pipe
+func (Pipe) Run(ctx *context.Context) error {
+	if !ctx.Config.Source.Enabled {
+		return pipe.Skip("source archive is not enabled")
+	}
+	name, err := tmpl.New(ctx).Apply(ctx.Config.Source.NameTemplate)
+	if err != nil {
+		return err
+	}
+	var filename = name + ".tar.gz"
+	var path = filepath.Join(ctx.Config.Dist, filename)
+	log.WithField("file", path).Info("creating source archive")
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+
+	var a = &sourceArchive{
+		a:      tar.New(file),
+		prefix: name,
+		names:  map[string]bool{},
+	}
+	if err := a.addCommit(ctx, ctx.Git.FullCommit); err != nil {
+		return err
+	}
+	if ctx.Config.Source.Vendor {
+		if err := a.addVendor(); err != nil {
+			return err
+		}
+	}
+	for _, glob := range ctx.Config.Source.Files {
+		files, err := zglob.Glob(glob)
+		if err != nil {
+			return errors.Wrapf(err, "globbing failed for pattern %s", glob)
+		}
+		for _, f := range files {
+			if err := a.addFile(f); err != nil {
+				return err
+			}
+		}
+	}
+	if err := a.a.Close(); err != nil {
+		return err
+	}
+	ctx.Artifacts.Add(artifact.Artifact{
+		Type: artifact.UploadableSourceArchive,
+		Name: filename,
+		Path: path,
+		Extra: map[string]interface{}{
+			"Format": "tar.gz",
+		},
+	})
+	return nil
+}
+
+// sourceArchive writes every entry inside the prefix folder, skipping the
+// ones already added
+type sourceArchive struct {
+	a      tar.Archive
+	prefix string
+	names  map[string]bool
+}
+
+// addCommit adds the files of the given commit, as exported by git archive
+func (s *sourceArchive) addCommit(ctx *context.Context, commit string) error {
+	/* #nosec */
+	var cmd = exec.CommandContext(ctx, "git", "archive", "--format=tar", commit)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	log.WithField("args", cmd.Args).Debug("running git")
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "failed to run git archive")
+	}
+	var r = stdtar.NewReader(out)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = cmd.Wait()
+			return errors.Wrapf(err, "failed to read git archive: %s", stderr.String())
+		}
+		if header.Typeflag == stdtar.TypeXGlobalHeader {
+			continue
+		}
+		if err := s.add(header.Name, header.FileInfo(), header.Linkname, r); err != nil {
+			_ = cmd.Wait()
+			return err
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "git archive failed: %s", stderr.String())
+	}
+	return nil
+}
+
+// addVendor adds the vendor folder, if there is one
+func (s *sourceArchive) addVendor() error {
+	if _, err := os.Stat("vendor"); os.IsNotExist(err) {
+		log.Warn("vendor folder not found, skipping it")
+		return nil
+	}
+	return filepath.Walk("vendor", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return s.addFile(path)
+	})
+}
+
+func (s *sourceArchive) addFile(path string) error {
+	var name = filepath.ToSlash(path)
+	if s.names[name] {
+		return nil
+	}
+	s.names[name] = true
+	log.WithField("file", path).Debug("adding file to source archive")
+	if err := s.a.Add(s.prefix+"/"+name, path); err != nil {
+		return errors.Wrapf(err, "failed to add %s to the source archive", path)
+	}
+	return nil
+}
+
+func (s *sourceArchive) add(name string, info os.FileInfo, link string, r io.Reader) error {
+	if s.names[name] {
+		return nil
+	}
+	s.names[name] = true
+	return s.a.AddEntry(s.prefix+"/"+name, info, link, r)
+}
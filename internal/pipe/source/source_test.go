@@ -0,0 +1,125 @@
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestDefault(t *testing.T) {
+	var ctx = context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, "{{ .ProjectName }}_{{ .Version }}_source", ctx.Config.Source.NameTemplate)
+}
+
+func TestSkip(t *testing.T) {
+	testlib.AssertSkipped(t, Pipe{}.Run(context.New(config.Project{})))
+}
+
+func TestRunPipe(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(folder, "cmd"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "main.go"), []byte("package main"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "cmd", "cmd.go"), []byte("package cmd"), 0644))
+	testlib.GitAdd(t)
+	testlib.GitCommit(t, "first")
+	require.NoError(t, os.MkdirAll(filepath.Join(folder, "vendor", "dep"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "vendor", "dep", "dep.go"), []byte("package dep"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "extra.txt"), []byte("extra"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(folder, "untracked.go"), []byte("package main"), 0644))
+	full, err := git.Clean(git.Run("rev-parse", "HEAD"))
+	require.NoError(t, err)
+
+	var dist = filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0755))
+	var ctx = context.New(config.Project{
+		ProjectName: "proj",
+		Dist:        dist,
+		Source: config.Source{
+			Enabled: true,
+			Vendor:  true,
+			Files:   []string{"extra.*"},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.FullCommit = full
+	ctx.Version = "1.0.0"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	var sources = ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableSourceArchive)).List()
+	require.Len(t, sources, 1)
+	require.Equal(t, "proj_1.0.0_source.tar.gz", sources[0].Name)
+	require.Equal(t, filepath.Join(dist, "proj_1.0.0_source.tar.gz"), sources[0].Path)
+	require.Equal(t, []string{
+		"proj_1.0.0_source/cmd/",
+		"proj_1.0.0_source/cmd/cmd.go",
+		"proj_1.0.0_source/main.go",
+		"proj_1.0.0_source/vendor/dep/dep.go",
+		"proj_1.0.0_source/extra.txt",
+	}, tarFiles(t, sources[0].Path))
+}
+
+func TestRunPipeInvalidCommit(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	var ctx = context.New(config.Project{
+		ProjectName: "proj",
+		Dist:        folder,
+		Source: config.Source{
+			Enabled:      true,
+			NameTemplate: "source",
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.FullCommit = "nope"
+	require.Contains(t, Pipe{}.Run(ctx).Error(), "git archive failed")
+}
+
+func TestRunPipeInvalidNameTemplate(t *testing.T) {
+	var ctx = context.New(config.Project{
+		Source: config.Source{
+			Enabled:      true,
+			NameTemplate: "{{ .Nope }",
+		},
+	})
+	require.Error(t, Pipe{}.Run(ctx))
+}
+
+func tarFiles(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	defer gr.Close()
+	var r = tar.NewReader(gr)
+	var paths []string
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		paths = append(paths, next.Name)
+	}
+	return paths
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/sign"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapcraft"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapshot"
+	"github.com/goreleaser/goreleaser/internal/pipe/source"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -40,10 +41,11 @@ var Pipeline = []Piper{
 	snapshot.Pipe{},        // snapshot version handling
 	dist.Pipe{},            // ensure ./dist is clean
 	effectiveconfig.Pipe{}, // writes the actual config (with defaults et al set) to dist
-	changelog.Pipe{},       // builds the release changelog
 	env.Pipe{},             // load and validate environment variables
+	changelog.Pipe{},       // builds the release changelog
 	build.Pipe{},           // build
 	archive.Pipe{},         // archive in tar.gz, zip or binary (which does no archiving at all)
+	source.Pipe{},          // archive the source code using git archive
 	nfpm.Pipe{},            // archive via fpm (deb, rpm) using "native" go impl
 	snapcraft.Pipe{},       // archive via snapcraft (snap)
 	checksums.Pipe{},       // checksums of the files
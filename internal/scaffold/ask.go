@@ -0,0 +1,57 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Ask asks which optional sections should be generated, using the
+// default sections of the project as the default answers.
+func Ask(in io.Reader, out io.Writer, project Project) (Sections, error) {
+	var sections = DefaultSections(project)
+	var r = bufio.NewReader(in)
+	var err error
+	for _, q := range []struct {
+		question string
+		answer   *bool
+	}{
+		{"Create archives with the binaries?", &sections.Archive},
+		{"Create deb and rpm packages?", &sections.NFPM},
+		{"Build and push Docker images?", &sections.Docker},
+	} {
+		*q.answer, err = confirm(r, out, q.question, *q.answer)
+		if err != nil {
+			return sections, err
+		}
+	}
+	return sections, nil
+}
+
+func confirm(r *bufio.Reader, out io.Writer, question string, def bool) (bool, error) {
+	var hint = "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		if _, err := fmt.Fprintf(out, "%s [%s] ", question, hint); err != nil {
+			return def, err
+		}
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return def, err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		if err == io.EOF {
+			return def, nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package scaffold
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+type build struct {
+	Main   string
+	Binary string
+}
+
+type data struct {
+	Project
+	Sections
+	Builds   []build
+	Files    []string
+	Image    string
+	Homepage string
+}
+
+// Render generates the config file for the given project
+func Render(project Project, sections Sections) (string, error) {
+	var d = data{
+		Project:  project,
+		Sections: sections,
+		Image:    strings.ToLower(project.Name),
+	}
+	if project.Repo.Owner != "" {
+		d.Image = strings.ToLower(project.Repo.String())
+		var host = project.Host
+		if host == "" {
+			host = "github.com"
+		}
+		d.Homepage = "https://" + host + "/" + project.Repo.String()
+	}
+	var mains = project.Mains
+	if len(mains) == 0 {
+		mains = []string{"."}
+	}
+	for _, main := range mains {
+		var b = build{Main: main, Binary: project.Binary(main)}
+		if main != "." {
+			b.Main = "./" + main
+		}
+		d.Builds = append(d.Builds, b)
+	}
+	if project.License != "" {
+		d.Files = append(d.Files, project.License)
+	}
+	d.Files = append(d.Files, project.Readmes...)
+
+	var out bytes.Buffer
+	if err := configTemplate.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// nolint: gochecknoglobals
+var configTemplate = template.Must(template.New("config").Delims("[[", "]]").Parse(
+	`# This is an example goreleaser.yaml file with some sane defaults.
+# Make sure to check the documentation at http://goreleaser.com
+before:
+  hooks:
+[[- if and .GoMod (not .Vendor) ]]
+    # downloads the dependencies declared in go.mod
+    - go mod download
+[[- end ]]
+    # you may remove this if you don't need go generate
+    - go generate ./...
+builds:
+[[- range .Builds ]]
+- main: [[ .Main ]]
+  binary: [[ .Binary ]]
+  env:
+  - CGO_ENABLED=0
+[[- if $.Vendor ]]
+  flags:
+  - -mod=vendor
+[[- end ]]
+[[- end ]]
+[[- if .Archive ]]
+archives:
+- replacements:
+    darwin: Darwin
+    linux: Linux
+    windows: Windows
+    386: i386
+    amd64: x86_64
+[[- if .Files ]]
+  files:
+[[- range .Files ]]
+  - [[ . ]]
+[[- end ]]
+[[- end ]]
+[[- end ]]
+[[- if .NFPM ]]
+nfpm:
+[[- if .Repo.Owner ]]
+  vendor: [[ .Repo.Owner ]]
+  homepage: [[ .Homepage ]]
+[[- end ]]
+  # maintainer: Your Name <you@example.com>
+  # description: A short description of [[ .Name ]]
+[[- if .LicenseID ]]
+  license: [[ .LicenseID ]]
+[[- end ]]
+  formats:
+  - deb
+  - rpm
+[[- end ]]
+[[- if .Docker ]]
+dockers:
+- dockerfile: [[ .Dockerfile ]]
+  binaries:
+[[- range .Builds ]]
+  - [[ .Binary ]]
+[[- end ]]
+  image_templates:
+  - '[[ .Image ]]:{{ .Tag }}'
+  - '[[ .Image ]]:latest'
+[[- end ]]
+checksum:
+  name_template: 'checksums.txt'
+snapshot:
+  name_template: "{{ .Tag }}-next"
+changelog:
+  sort: asc
+  filters:
+    exclude:
+    - '^docs:'
+    - '^test:'
+`))
@@ -0,0 +1,155 @@
+// Package scaffold inspects a Go project and generates a goreleaser
+// configuration file tailored to it.
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/builders/golang"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/pkg/config"
+)
+
+// Project holds what was found out about the project being initialized
+type Project struct {
+	Name       string
+	Mains      []string
+	GoMod      bool
+	Vendor     bool
+	Dockerfile string
+	License    string
+	LicenseID  string
+	Readmes    []string
+	Repo       config.Repo
+	// Host of the git remote, e.g. github.com
+	Host string
+}
+
+// Sections are the optional sections of the generated config
+type Sections struct {
+	Archive bool
+	NFPM    bool
+	Docker  bool
+}
+
+// DefaultSections enables all sections the project seems to need
+func DefaultSections(project Project) Sections {
+	return Sections{
+		Archive: true,
+		NFPM:    true,
+		Docker:  project.Dockerfile != "",
+	}
+}
+
+// Inspect inspects the project in the given folder, which should be the
+// current working directory, as git commands run on it.
+func Inspect(dir string) (Project, error) {
+	var project = Project{
+		GoMod:  exists(filepath.Join(dir, "go.mod")),
+		Vendor: exists(filepath.Join(dir, "vendor")),
+	}
+	mains, err := golang.FindMains(dir)
+	if err != nil {
+		return project, err
+	}
+	for _, main := range mains {
+		rel, err := filepath.Rel(dir, main)
+		if err != nil {
+			return project, err
+		}
+		project.Mains = append(project.Mains, filepath.ToSlash(rel))
+	}
+	if remote, err := git.RemoteURL(); err == nil {
+		project.Repo = git.ExtractRepoFromURL(remote)
+		project.Host = git.ExtractHostFromURL(remote)
+	} else {
+		log.WithError(err).Debug("could not detect the git remote")
+	}
+	project.Name = project.Repo.Name
+	if project.Name == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return project, err
+		}
+		project.Name = filepath.Base(abs)
+	}
+	for _, name := range []string{"Dockerfile", "dockerfile"} {
+		if exists(filepath.Join(dir, name)) {
+			project.Dockerfile = name
+			break
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return project, err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		var name = strings.ToLower(file.Name())
+		switch {
+		case strings.HasPrefix(name, "license"), strings.HasPrefix(name, "licence"),
+			strings.HasPrefix(name, "copying"):
+			if project.License != "" {
+				continue
+			}
+			project.License = file.Name()
+			bts, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				return project, err
+			}
+			project.LicenseID = detectLicense(string(bts))
+		case strings.HasPrefix(name, "readme"):
+			project.Readmes = append(project.Readmes, file.Name())
+		}
+	}
+	sort.Strings(project.Readmes)
+	return project, nil
+}
+
+// Binary returns the name of the binary built from the given main package
+func (p Project) Binary(main string) string {
+	if main == "." {
+		return p.Name
+	}
+	return filepath.Base(main)
+}
+
+// nolint: gochecknoglobals
+var licenses = []struct {
+	id string
+	re *regexp.Regexp
+}{
+	{"MIT", regexp.MustCompile(`(?i)\bMIT License\b|Permission is hereby granted, free of charge`)},
+	{"Apache-2.0", regexp.MustCompile(`(?i)Apache License,?\s+Version 2\.0`)},
+	{"MPL-2.0", regexp.MustCompile(`(?i)Mozilla Public License,?\s+(Version|v\.) 2\.0`)},
+	{"AGPL-3.0", regexp.MustCompile(`(?i)GNU AFFERO GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"LGPL-3.0", regexp.MustCompile(`(?i)GNU LESSER GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"GPL-3.0", regexp.MustCompile(`(?i)GNU GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"GPL-2.0", regexp.MustCompile(`(?i)GNU GENERAL PUBLIC LICENSE\s+Version 2`)},
+	{"BSD-3-Clause", regexp.MustCompile(`(?i)Neither the name of`)},
+	{"BSD-2-Clause", regexp.MustCompile(`(?i)Redistribution and use in source and binary forms`)},
+	{"Unlicense", regexp.MustCompile(`(?i)This is free and unencumbered software`)},
+}
+
+// detectLicense guesses the SPDX identifier of the given license text
+func detectLicense(text string) string {
+	for _, license := range licenses {
+		if license.re.MatchString(text) {
+			return license.id
+		}
+	}
+	return ""
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,161 @@
+package scaffold
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update .golden files")
+
+func TestInspect(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:goreleaser/fake.git")
+	for _, dir := range []string{"cmd/foo", "cmd/bar", "vendor"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(folder, dir), 0755))
+	}
+	writeFile(t, "cmd/foo/main.go", "package main\nfunc main() {}")
+	writeFile(t, "cmd/bar/main.go", "package main\nfunc main() {}")
+	writeFile(t, "go.mod", "module github.com/goreleaser/fake")
+	writeFile(t, "Dockerfile", "FROM scratch")
+	writeFile(t, "LICENSE.md", "MIT License\n\nCopyright (c) 2018")
+	writeFile(t, "README.md", "# fake")
+
+	project, err := Inspect(".")
+	require.NoError(t, err)
+	assert.Equal(t, Project{
+		Name:       "fake",
+		Mains:      []string{"cmd/bar", "cmd/foo"},
+		GoMod:      true,
+		Vendor:     true,
+		Dockerfile: "Dockerfile",
+		License:    "LICENSE.md",
+		LicenseID:  "MIT",
+		Readmes:    []string{"README.md"},
+		Repo:       config.Repo{Owner: "goreleaser", Name: "fake"},
+		Host:       "github.com",
+	}, project)
+	assert.Equal(t, Sections{Archive: true, NFPM: true, Docker: true}, DefaultSections(project))
+}
+
+func TestInspectEmptyFolder(t *testing.T) {
+	folder, back := testlib.Mktmp(t)
+	defer back()
+	project, err := Inspect(".")
+	require.NoError(t, err)
+	assert.Equal(t, Project{Name: filepath.Base(folder)}, project)
+	assert.Equal(t, Sections{Archive: true, NFPM: true}, DefaultSections(project))
+}
+
+func TestDetectLicense(t *testing.T) {
+	for text, id := range map[string]string{
+		"MIT License": "MIT",
+		"Apache License\n                           Version 2.0": "Apache-2.0",
+		"GNU GENERAL PUBLIC LICENSE\n   Version 3, 29 June 2007": "GPL-3.0",
+		"GNU GENERAL PUBLIC LICENSE\n   Version 2, June 1991":    "GPL-2.0",
+		"GNU LESSER GENERAL PUBLIC LICENSE\n   Version 3":        "LGPL-3.0",
+		"Mozilla Public License Version 2.0":                     "MPL-2.0",
+		"Neither the name of the copyright holder":               "BSD-3-Clause",
+		"This is free and unencumbered software":                 "Unlicense",
+		"All rights reserved":                                    "",
+	} {
+		assert.Equal(t, id, detectLicense(text), text)
+	}
+}
+
+func TestRender(t *testing.T) {
+	for name, tt := range map[string]struct {
+		project  Project
+		sections Sections
+	}{
+		"minimal": {
+			project: Project{Name: "foo"},
+		},
+		"single": {
+			project: Project{
+				Name:      "foo",
+				Mains:     []string{"."},
+				GoMod:     true,
+				License:   "LICENSE",
+				LicenseID: "Apache-2.0",
+				Readmes:   []string{"README.md"},
+				Repo:      config.Repo{Owner: "goreleaser", Name: "foo"},
+			},
+			sections: Sections{Archive: true, NFPM: true},
+		},
+		"multiple": {
+			project: Project{
+				Name:       "Foo",
+				Mains:      []string{"cmd/bar", "cmd/foo"},
+				GoMod:      true,
+				Vendor:     true,
+				Dockerfile: "Dockerfile",
+			},
+			sections: Sections{Archive: true, NFPM: true, Docker: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := Render(tt.project, tt.sections)
+			require.NoError(t, err)
+			_, err = config.LoadReader(strings.NewReader(out))
+			require.NoError(t, err)
+
+			var golden = filepath.Join("testdata", name+".yml.golden")
+			if *update {
+				require.NoError(t, ioutil.WriteFile(golden, []byte(out), 0655))
+			}
+			bts, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(bts), out)
+		})
+	}
+}
+
+func TestRenderGitLabHomepage(t *testing.T) {
+	out, err := Render(Project{
+		Name: "foo",
+		Repo: config.Repo{Owner: "group", Name: "foo"},
+		Host: "gitlab.example.com",
+	}, Sections{NFPM: true})
+	require.NoError(t, err)
+	assert.Contains(t, out, "  homepage: https://gitlab.example.com/group/foo\n")
+}
+
+func TestAsk(t *testing.T) {
+	var out bytes.Buffer
+	sections, err := Ask(
+		strings.NewReader("\nwhat\nno\nY\n"),
+		&out,
+		Project{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, Sections{Archive: true, NFPM: false, Docker: true}, sections)
+	assert.Equal(
+		t,
+		"Create archives with the binaries? [Y/n] "+
+			"Create deb and rpm packages? [Y/n] Create deb and rpm packages? [Y/n] "+
+			"Build and push Docker images? [y/N] ",
+		out.String(),
+	)
+}
+
+func TestAskEOF(t *testing.T) {
+	sections, err := Ask(strings.NewReader(""), ioutil.Discard, Project{Dockerfile: "Dockerfile"})
+	require.NoError(t, err)
+	assert.Equal(t, Sections{Archive: true, NFPM: true, Docker: true}, sections)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
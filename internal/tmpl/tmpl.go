@@ -3,6 +3,7 @@ package tmpl
 
 import (
 	"bytes"
+	"io/ioutil"
 	"text/template"
 	"time"
 
@@ -14,10 +15,11 @@ import (
 
 // Template holds data that can be applied to a template string
 type Template struct {
-	fields fields
+	fields Fields
 }
 
-type fields map[string]interface{}
+// Fields that will be available to the template engine.
+type Fields map[string]interface{}
 
 const (
 	// general keys
@@ -46,7 +48,7 @@ const (
 // New Template
 func New(ctx *context.Context) *Template {
 	return &Template{
-		fields: fields{
+		fields: Fields{
 			projectName: ctx.Config.ProjectName,
 			version:     ctx.Version,
 			tag:         ctx.Git.CurrentTag,
@@ -75,6 +77,15 @@ func (t *Template) WithArtifact(a artifact.Artifact, replacements map[string]str
 	return t
 }
 
+// WithExtraFields adds the given fields to the template, overriding the
+// ones with the same name
+func (t *Template) WithExtraFields(f Fields) *Template {
+	for k, v := range f {
+		t.fields[k] = v
+	}
+	return t
+}
+
 // Apply applies the given string against the fields stored in the template.
 func (t *Template) Apply(s string) (string, error) {
 	var out bytes.Buffer
@@ -102,6 +113,16 @@ func (t *Template) Apply(s string) (string, error) {
 	return out.String(), err
 }
 
+// ApplyFile applies the contents of the file at the given path against the
+// fields stored in the template.
+func (t *Template) ApplyFile(path string) (string, error) {
+	bts, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return "", err
+	}
+	return t.Apply(string(bts))
+}
+
 func replace(replacements map[string]string, original string) string {
 	result := replacements[original]
 	if result == "" {
@@ -156,3 +156,17 @@ func TestInvalidSemver(t *testing.T) {
 	assert.Empty(t, result)
 	assert.EqualError(t, err, `tmpl: Invalid Semantic Version`)
 }
+
+func TestApplyFile(t *testing.T) {
+	var ctx = context.New(config.Project{ProjectName: "proj"})
+	ctx.Git.CurrentTag = "v1.2.4"
+	ctx.Version = "1.2.4"
+	result, err := New(ctx).
+		WithArtifact(artifact.Artifact{Goos: "linux"}, map[string]string{}).
+		ApplyFile("testdata/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "proj 1.2.4 linux", result)
+
+	_, err = New(ctx).ApplyFile("testdata/nope.txt")
+	assert.Error(t, err)
+}
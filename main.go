@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,10 +14,14 @@ import (
 	"github.com/apex/log/handlers/cli"
 	"github.com/caarlos0/ctrlc"
 	"github.com/fatih/color"
+	"github.com/goreleaser/goreleaser/internal/migrate"
 	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/pipe/changelog"
 	"github.com/goreleaser/goreleaser/internal/pipeline"
+	"github.com/goreleaser/goreleaser/internal/scaffold"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/goreleaser/pkg/jsonschema"
 )
 
 // nolint: gochecknoglobals
@@ -26,16 +32,27 @@ var (
 )
 
 type releaseOptions struct {
-	Config       string
-	ReleaseNotes string
-	Snapshot     bool
-	SkipPublish  bool
-	SkipSign     bool
-	SkipValidate bool
-	RmDist       bool
-	Debug        bool
-	Parallelism  int
-	Timeout      time.Duration
+	Config        string
+	ReleaseNotes  string
+	ReleaseHeader string
+	ReleaseFooter string
+	Snapshot      bool
+	SkipPublish   bool
+	SkipSign      bool
+	SkipValidate  bool
+	RmDist        bool
+	Strict        bool
+	Debug         bool
+	Parallelism   int
+	Timeout       time.Duration
+}
+
+type changelogOptions struct {
+	Config string
+	From   string
+	To     string
+	Format string
+	Output string
 }
 
 func main() {
@@ -50,17 +67,31 @@ func main() {
 
 	var app = kingpin.New("goreleaser", "Deliver Go binaries as fast and easily as possible")
 	var initCmd = app.Command("init", "Generates a .goreleaser.yml file").Alias("i")
+	var interactive = initCmd.Flag("interactive", "Asks which sections should be generated").Bool()
 	var releaseCmd = app.Command("release", "Releases the current project").Alias("r").Default()
 	var config = releaseCmd.Flag("config", "Load configuration from file").Short('c').Short('f').PlaceHolder(".goreleaser.yml").String()
 	var releaseNotes = releaseCmd.Flag("release-notes", "Load custom release notes from a markdown file").PlaceHolder("notes.md").String()
+	var releaseHeader = releaseCmd.Flag("release-header", "Load custom release notes header from a markdown file").PlaceHolder("header.md").String()
+	var releaseFooter = releaseCmd.Flag("release-footer", "Load custom release notes footer from a markdown file").PlaceHolder("footer.md").String()
 	var snapshot = releaseCmd.Flag("snapshot", "Generate an unversioned snapshot release, skipping all validations and without publishing any artifacts").Bool()
 	var skipPublish = releaseCmd.Flag("skip-publish", "Generates all artifacts but does not publish them anywhere").Bool()
 	var skipSign = releaseCmd.Flag("skip-sign", "Skips signing the artifacts").Bool()
 	var skipValidate = releaseCmd.Flag("skip-validate", "Skips all git sanity checks").Bool()
 	var rmDist = releaseCmd.Flag("rm-dist", "Remove the dist folder before building").Bool()
+	var strict = releaseCmd.Flag("strict", "Fails if any deprecated option is used").Bool()
 	var parallelism = releaseCmd.Flag("parallelism", "Amount of slow tasks to do in concurrently").Short('p').Default("4").Int() // TODO: use runtime.NumCPU here?
 	var debug = releaseCmd.Flag("debug", "Enable debug mode").Bool()
 	var timeout = releaseCmd.Flag("timeout", "Timeout to the entire release process").Default("30m").Duration()
+	var jsonschemaCmd = app.Command("jsonschema", "Outputs the JSON schema of the .goreleaser.yml file").Alias("schema")
+	var jsonschemaOutput = jsonschemaCmd.Flag("output", "Where to save the JSON schema, - means stdout").Short('o').Default("-").String()
+	var migrateCmd = app.Command("migrate", "Rewrites deprecated options of the config file")
+	var migrateConfig = migrateCmd.Flag("config", "Configuration file to migrate").Short('c').Short('f').PlaceHolder(".goreleaser.yml").String()
+	var changelogCmd = app.Command("changelog", "Generates the changelog of a range of commits without releasing")
+	var changelogConfig = changelogCmd.Flag("config", "Load configuration from file").Short('c').Short('f').PlaceHolder(".goreleaser.yml").String()
+	var changelogFrom = changelogCmd.Flag("from", "Ref to generate the changelog from, defaults to the tag before --to").PlaceHolder("v1.0.0").String()
+	var changelogTo = changelogCmd.Flag("to", "Ref to generate the changelog up to").Default("HEAD").String()
+	var changelogFormat = changelogCmd.Flag("format", "Format of the changelog, markdown or json").Default("markdown").Enum("markdown", "json")
+	var changelogOutput = changelogCmd.Flag("output", "Where to save the changelog, - means stdout").Short('o').Default("-").String()
 
 	app.Version(fmt.Sprintf("%v, commit %v, built at %v", version, commit, date))
 	app.VersionFlag.Short('v')
@@ -69,26 +100,54 @@ func main() {
 	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
 	case initCmd.FullCommand():
 		var filename = ".goreleaser.yml"
-		if err := initProject(filename); err != nil {
+		if err := initProject(filename, *interactive); err != nil {
 			log.WithError(err).Error("failed to init project")
 			terminate(1)
 			return
 		}
 		log.WithField("file", filename).Info("config created; please edit accordingly to your needs")
+	case jsonschemaCmd.FullCommand():
+		if err := writeJSONSchema(*jsonschemaOutput); err != nil {
+			log.WithError(err).Error("failed to generate JSON schema")
+			terminate(1)
+			return
+		}
+	case migrateCmd.FullCommand():
+		if err := migrateProject(*migrateConfig); err != nil {
+			log.WithError(err).Error("failed to migrate config")
+			terminate(1)
+			return
+		}
+	case changelogCmd.FullCommand():
+		var options = changelogOptions{
+			Config: *changelogConfig,
+			From:   *changelogFrom,
+			To:     *changelogTo,
+			Format: *changelogFormat,
+			Output: *changelogOutput,
+		}
+		if err := changelogProject(options); err != nil {
+			log.WithError(err).Error("failed to generate changelog")
+			terminate(1)
+			return
+		}
 	case releaseCmd.FullCommand():
 		start := time.Now()
 		log.Infof(color.New(color.Bold).Sprintf("releasing using goreleaser %s...", version))
 		var options = releaseOptions{
-			Config:       *config,
-			ReleaseNotes: *releaseNotes,
-			Snapshot:     *snapshot,
-			SkipPublish:  *skipPublish,
-			SkipValidate: *skipValidate,
-			SkipSign:     *skipSign,
-			RmDist:       *rmDist,
-			Parallelism:  *parallelism,
-			Debug:        *debug,
-			Timeout:      *timeout,
+			Config:        *config,
+			ReleaseNotes:  *releaseNotes,
+			ReleaseHeader: *releaseHeader,
+			ReleaseFooter: *releaseFooter,
+			Snapshot:      *snapshot,
+			SkipPublish:   *skipPublish,
+			SkipValidate:  *skipValidate,
+			SkipSign:      *skipSign,
+			RmDist:        *rmDist,
+			Strict:        *strict,
+			Parallelism:   *parallelism,
+			Debug:         *debug,
+			Timeout:       *timeout,
 		}
 		if err := releaseProject(options); err != nil {
 			log.WithError(err).Errorf(color.New(color.Bold).Sprintf("release failed after %0.2fs", time.Since(start).Seconds()))
@@ -125,14 +184,36 @@ func releaseProject(options releaseOptions) error {
 		log.WithField("file", options.ReleaseNotes).Debugf("custom release notes: \n%s", string(bts))
 		ctx.ReleaseNotes = string(bts)
 	}
+	if ctx.ReleaseHeader, err = loadReleaseFile(options.ReleaseHeader, "header"); err != nil {
+		return err
+	}
+	if ctx.ReleaseFooter, err = loadReleaseFile(options.ReleaseFooter, "footer"); err != nil {
+		return err
+	}
 	ctx.Snapshot = options.Snapshot
 	ctx.SkipPublish = ctx.Snapshot || options.SkipPublish
 	ctx.SkipValidate = ctx.Snapshot || options.SkipValidate
 	ctx.SkipSign = options.SkipSign
 	ctx.RmDist = options.RmDist
+	ctx.Strict = options.Strict
 	return doRelease(ctx)
 }
 
+// loadReleaseFile reads the custom release notes header or footer at the
+// given path, if any
+func loadReleaseFile(path, kind string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	log.WithField("file", path).Infof("loaded custom release notes %s", kind)
+	log.WithField("file", path).Debugf("custom release notes %s: \n%s", kind, string(bts))
+	return string(bts), nil
+}
+
 func doRelease(ctx *context.Context) error {
 	defer func() { cli.Default.Padding = 3 }()
 	var release = func() error {
@@ -160,28 +241,132 @@ func handle(err error) error {
 	return err
 }
 
-// InitProject creates an example goreleaser.yml in the current directory
-func initProject(filename string) error {
+// InitProject creates a goreleaser.yml tailored to the project in the
+// current directory
+func initProject(filename string, interactive bool) error {
 	if _, err := os.Stat(filename); !os.IsNotExist(err) {
 		if err != nil {
 			return err
 		}
 		return fmt.Errorf("%s already exists", filename)
 	}
+	project, err := scaffold.Inspect(".")
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"mains":      project.Mains,
+		"gomod":      project.GoMod,
+		"vendor":     project.Vendor,
+		"dockerfile": project.Dockerfile,
+		"license":    project.LicenseID,
+		"repo":       project.Repo.String(),
+	}).Info("inspected project")
+	var sections = scaffold.DefaultSections(project)
+	if interactive {
+		if sections, err = scaffold.Ask(os.Stdin, os.Stdout, project); err != nil {
+			return err
+		}
+	}
+	content, err := scaffold.Render(project, sections)
+	if err != nil {
+		return err
+	}
 	log.Infof(color.New(color.Bold).Sprintf("Generating %s file", filename))
-	return ioutil.WriteFile(filename, []byte(exampleConfig), 0644)
+	return ioutil.WriteFile(filename, []byte(content), 0644)
+}
+
+// writeJSONSchema writes the JSON schema of the config file to the given path
+func writeJSONSchema(path string) error {
+	bts, err := json.MarshalIndent(jsonschema.Reflect(&config.Project{}), "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err = fmt.Fprintln(os.Stdout, string(bts))
+		return err
+	}
+	log.WithField("file", path).Info("writing JSON schema")
+	return ioutil.WriteFile(path, bts, 0644)
+}
+
+// migrateProject rewrites the deprecated options of the given config file
+func migrateProject(path string) error {
+	if path == "" {
+		path = findConfig()
+	}
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	out, migrated, err := migrate.Migrate(bts)
+	if err != nil {
+		return err
+	}
+	if len(migrated) == 0 {
+		log.WithField("file", path).Info("nothing to migrate")
+		return nil
+	}
+	// make sure the result is still a valid config file
+	if _, err := config.LoadReader(bytes.NewReader(out)); err != nil {
+		return err
+	}
+	for _, property := range migrated {
+		log.WithField("property", property).Info("migrated")
+	}
+	log.WithField("file", path).Info("writing migrated config")
+	return ioutil.WriteFile(path, out, 0644)
+}
+
+// changelogProject writes the changelog of the given range of commits,
+// following the changelog settings of the config file
+func changelogProject(options changelogOptions) error {
+	cfg, err := loadConfig(options.Config)
+	if err != nil {
+		return err
+	}
+	var ctx = context.New(cfg)
+	// only needed to list pull requests
+	ctx.ChangelogToken = os.Getenv("GITHUB_TOKEN")
+	if cfg.Changelog.Source == "gitlab" {
+		ctx.ChangelogToken = os.Getenv("GITLAB_TOKEN")
+	}
+	out, err := changelog.Generate(ctx, options.From, options.To, options.Format)
+	if err != nil {
+		return err
+	}
+	if options.Output == "-" {
+		_, err = fmt.Fprint(os.Stdout, out)
+		return err
+	}
+	log.WithField("file", options.Output).Info("writing changelog")
+	return ioutil.WriteFile(options.Output, []byte(out), 0644)
+}
+
+// nolint: gochecknoglobals
+var configFiles = [4]string{
+	".goreleaser.yml",
+	".goreleaser.yaml",
+	"goreleaser.yml",
+	"goreleaser.yaml",
+}
+
+// findConfig returns the first known config file that exists, defaulting to
+// the first one
+func findConfig() string {
+	for _, f := range configFiles {
+		if _, err := os.Stat(f); err == nil {
+			return f
+		}
+	}
+	return configFiles[0]
 }
 
 func loadConfig(path string) (config.Project, error) {
 	if path != "" {
 		return config.Load(path)
 	}
-	for _, f := range [4]string{
-		".goreleaser.yml",
-		".goreleaser.yaml",
-		"goreleaser.yml",
-		"goreleaser.yaml",
-	} {
+	for _, f := range configFiles {
 		proj, err := config.Load(f)
 		if err != nil && os.IsNotExist(err) {
 			continue
@@ -193,34 +378,3 @@ func loadConfig(path string) (config.Project, error) {
 	log.Warn("could not load config, using defaults")
 	return config.Project{}, nil
 }
-
-// nolint: gochecknoglobals
-var exampleConfig = `# This is an example goreleaser.yaml file with some sane defaults.
-# Make sure to check the documentation at http://goreleaser.com
-before:
-  hooks:
-    # you may remove this if you don't use vgo
-    - go mod download
-    # you may remove this if you don't need go generate
-    - go generate ./...
-builds:
-- env:
-  - CGO_ENABLED=0
-archive:
-  replacements:
-    darwin: Darwin
-    linux: Linux
-    windows: Windows
-    386: i386
-    amd64: x86_64
-checksum:
-  name_template: 'checksums.txt'
-snapshot:
-  name_template: "{{ .Tag }}-next"
-changelog:
-  sort: asc
-  filters:
-    exclude:
-    - '^docs:'
-    - '^test:'
-`
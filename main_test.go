@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -76,6 +77,18 @@ func TestReleaseNotesFileDontExist(t *testing.T) {
 	assert.Error(t, releaseProject(params))
 }
 
+func TestReleaseHeaderFileDontExist(t *testing.T) {
+	params := testParams()
+	params.ReleaseHeader = "/this/also/wont/exist"
+	assert.Error(t, releaseProject(params))
+}
+
+func TestReleaseFooterFileDontExist(t *testing.T) {
+	params := testParams()
+	params.ReleaseFooter = "/this/also/wont/exist"
+	assert.Error(t, releaseProject(params))
+}
+
 func TestCustomReleaseNotesFile(t *testing.T) {
 	_, back := setup(t)
 	defer back()
@@ -98,7 +111,7 @@ func TestInitProject(t *testing.T) {
 	_, back := setup(t)
 	defer back()
 	var filename = "test_goreleaser.yml"
-	assert.NoError(t, initProject(filename))
+	assert.NoError(t, initProject(filename, false))
 
 	file, err := os.Open(filename)
 	assert.NoError(t, err)
@@ -109,12 +122,32 @@ func TestInitProject(t *testing.T) {
 	assert.NoError(t, yaml.Unmarshal(out, &config))
 }
 
+func TestInitProjectMultipleMains(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	assert.NoError(t, os.MkdirAll("cmd/foo", 0755))
+	createFile(t, "cmd/foo/main.go", "package main\nfunc main() {println(0)}")
+	createFile(t, "Dockerfile", "FROM scratch")
+	var filename = "test_goreleaser.yml"
+	assert.NoError(t, initProject(filename, false))
+
+	cfg, err := config.Load(filename)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Builds, 2)
+	assert.Equal(t, ".", cfg.Builds[0].Main)
+	assert.Equal(t, "fake", cfg.Builds[0].Binary)
+	assert.Equal(t, "./cmd/foo", cfg.Builds[1].Main)
+	assert.Equal(t, "foo", cfg.Builds[1].Binary)
+	assert.Len(t, cfg.Dockers, 1)
+	assert.Equal(t, []string{"fake", "foo"}, cfg.Dockers[0].Binaries)
+}
+
 func TestInitProjectFileExist(t *testing.T) {
 	_, back := setup(t)
 	defer back()
 	var filename = "test_goreleaser.yml"
 	createFile(t, filename, "")
-	assert.Error(t, initProject(filename))
+	assert.Error(t, initProject(filename, false))
 }
 
 func TestInitProjectDefaultPipeFails(t *testing.T) {
@@ -122,7 +155,117 @@ func TestInitProjectDefaultPipeFails(t *testing.T) {
 	defer back()
 	var filename = "test_goreleaser.yml"
 	assert.NoError(t, os.Chmod(folder, 0000))
-	assert.EqualError(t, initProject(filename), `stat test_goreleaser.yml: permission denied`)
+	assert.EqualError(t, initProject(filename, false), `stat test_goreleaser.yml: permission denied`)
+}
+
+func TestJSONSchema(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	var filename = "schema.json"
+	assert.NoError(t, writeJSONSchema(filename))
+
+	bts, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bts, &schema))
+	assert.Equal(t, "#/definitions/Project", schema["$ref"])
+	assert.Contains(t, schema["definitions"], "Archive")
+}
+
+func TestReleaseProjectStrict(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	createFile(t, "goreleaser.yml", "git:\n  short_hash: true\n")
+	var params = testParams()
+	params.Strict = true
+	assert.EqualError(
+		t,
+		releaseProject(params),
+		"`git.short_hash` is deprecated and strict mode is enabled, check https://goreleaser.com/deprecations#git-short-hash for more info",
+	)
+}
+
+func TestMigrateProject(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	createFile(t, ".goreleaser.yml", "dockers:\n# the image\n- image: foo/bar\n  binary: foo\n")
+	assert.NoError(t, migrateProject(""))
+	bts, err := ioutil.ReadFile(".goreleaser.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "dockers:\n  # the image\n  - image_templates:\n      - foo/bar:{{ .Version }}\n    binaries:\n      - foo\n", string(bts))
+}
+
+func TestMigrateProjectNothingToDo(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	bts, err := ioutil.ReadFile("goreleaser.yml")
+	assert.NoError(t, err)
+	assert.NoError(t, migrateProject("goreleaser.yml"))
+	after, err := ioutil.ReadFile("goreleaser.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, string(bts), string(after))
+}
+
+func TestMigrateProjectFileDoesntExist(t *testing.T) {
+	assert.Error(t, migrateProject("/this/wont/exist"))
+}
+
+func TestMigrateProjectInvalidResult(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	createFile(t, "goreleaser.yml", "dockers:\n- image: foo/bar\nnot_a_valid_key: true\n")
+	assert.Error(t, migrateProject("goreleaser.yml"))
+}
+
+func TestChangelogProject(t *testing.T) {
+	folder, back := setup(t)
+	defer back()
+	testlib.GitCommit(t, "feat: not released yet")
+	createFile(t, "goreleaser.yml", "changelog:\n  filters:\n    exclude:\n    - ^assd\n")
+	var output = filepath.Join(folder, "changelog.md")
+	assert.NoError(t, changelogProject(changelogOptions{
+		To:     "HEAD",
+		Format: "markdown",
+		Output: output,
+	}))
+	bts, err := ioutil.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bts), "## Changelog")
+	assert.Contains(t, string(bts), "feat: not released yet")
+	assert.NotContains(t, string(bts), "assssf")
+
+	assert.NoError(t, changelogProject(changelogOptions{
+		From:   "v0.0.1",
+		To:     "v0.0.2",
+		Format: "json",
+		Output: output,
+	}))
+	bts, err = ioutil.ReadFile(output)
+	assert.NoError(t, err)
+	var result struct {
+		PreviousTag string
+		CurrentTag  string
+		CompareURL  string
+		Commits     []struct {
+			Subject string
+		}
+	}
+	assert.NoError(t, json.Unmarshal(bts, &result))
+	assert.Equal(t, "v0.0.1", result.PreviousTag)
+	assert.Equal(t, "v0.0.2", result.CurrentTag)
+	assert.Equal(t, "https://github.com/goreleaser/fake/compare/v0.0.1...v0.0.2", result.CompareURL)
+	assert.Len(t, result.Commits, 2)
+	assert.Equal(t, "assssf", result.Commits[0].Subject)
+	assert.Equal(t, "asas89d", result.Commits[1].Subject)
+}
+
+func TestChangelogProjectInvalidFormat(t *testing.T) {
+	_, back := setup(t)
+	defer back()
+	assert.EqualError(t, changelogProject(changelogOptions{
+		Format: "html",
+		Output: "-",
+	}), "invalid changelog format: html")
 }
 
 func testParams() releaseOptions {
@@ -1,21 +1,85 @@
-// Package archive provides tar.gz and zip archiving
+// Package archive provides tar (plain, gz, xz and zst), gz and zip archiving
 package archive
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
-
 	"path/filepath"
+	"time"
 
+	"github.com/goreleaser/goreleaser/pkg/archive/gzip"
 	"github.com/goreleaser/goreleaser/pkg/archive/tar"
 	"github.com/goreleaser/goreleaser/pkg/archive/zip"
 )
 
+// Formats lists the supported archive formats
+// nolint: gochecknoglobals
+var Formats = []string{"tar.gz", "tar.xz", "tar.zst", "tar", "gz", "zip"}
+
 // Archive represents a compression archive files from disk can be written to.
 type Archive interface {
 	Close() error
 	Add(name, path string) error
 }
 
+// EntryArchive is an Archive which can also override the metadata of the
+// files it adds and hold generated entries, with no file on disk. All the
+// archives of this package implement it.
+type EntryArchive interface {
+	Archive
+	AddFile(name, path string, mode os.FileMode, mtime time.Time) error
+	AddEntry(name string, info os.FileInfo, link string, r io.Reader) error
+}
+
+// AddFile adds a file to the archive overriding its mode and mtime, unless
+// they are zero. Archives which are not an EntryArchive can only add it with
+// its own metadata.
+func AddFile(a Archive, name, path string, mode os.FileMode, mtime time.Time) error {
+	if e, ok := a.(EntryArchive); ok {
+		return e.AddFile(name, path, mode, mtime)
+	}
+	if mode != 0 || !mtime.IsZero() {
+		return fmt.Errorf("%T archives can't override the mode and mtime of %s", a, name)
+	}
+	return a.Add(name, path)
+}
+
+// AddEntry adds an entry with the given header info to the archive, which
+// must be an EntryArchive.
+func AddEntry(a Archive, name string, info os.FileInfo, link string, r io.Reader) error {
+	if e, ok := a.(EntryArchive); ok {
+		return e.AddEntry(name, info, link, r)
+	}
+	return fmt.Errorf("%T archives can't hold generated entries like %s", a, name)
+}
+
+// Header returns the header info of an entry to add with AddEntry.
+// The mode holds both the permissions and the type of the entry, e.g.
+// os.ModeDir or os.ModeSymlink, and size is the length of its content.
+func Header(mode os.FileMode, mtime time.Time, size int64) os.FileInfo {
+	return header{mode: mode, mtime: mtime, size: size}
+}
+
+type header struct {
+	mode  os.FileMode
+	mtime time.Time
+	size  int64
+}
+
+func (h header) Name() string       { return "" }
+func (h header) Size() int64        { return h.size }
+func (h header) Mode() os.FileMode  { return h.mode }
+func (h header) ModTime() time.Time { return h.mtime }
+func (h header) IsDir() bool        { return h.mode.IsDir() }
+func (h header) Sys() interface{}   { return nil }
+
+// AddBytes adds a regular file with the given content to the archive
+func AddBytes(a Archive, name string, content []byte, mode os.FileMode, mtime time.Time) error {
+	return AddEntry(a, name, Header(mode, mtime, int64(len(content))), "", bytes.NewReader(content))
+}
+
 // New archive
 // If the exentions of the target file is .zip, the archive will be in the zip
 // format, otherwise, it will be a tar.gz archive.
@@ -25,3 +89,42 @@ func New(file *os.File) Archive {
 	}
 	return tar.New(file)
 }
+
+// NewFormat archive in the given format, one of Formats, using the given
+// compression level. Zero means the default level of the format.
+func NewFormat(w io.Writer, format string, level int) (Archive, error) {
+	switch format {
+	case "tar.gz":
+		return tar.NewGz(w, level)
+	case "tar.xz":
+		return tar.NewXz(w, level)
+	case "tar.zst":
+		return tar.NewZst(w, level)
+	case "tar":
+		return tar.NewPlain(w), nil
+	case "gz":
+		return gzip.NewWithLevel(w, level)
+	case "zip":
+		return zip.NewWithLevel(w, level)
+	}
+	return nil, fmt.Errorf("invalid archive format: %s", format)
+}
+
+// NewReproducible archive in the given format, like NewFormat, whose entries have
+// normalized metadata: the given mtime, no owner and either 0644 or 0755
+// permissions.
+func NewReproducible(w io.Writer, format string, level int, mtime time.Time) (Archive, error) {
+	a, err := NewFormat(w, format, level)
+	if err != nil {
+		return nil, err
+	}
+	switch a := a.(type) {
+	case tar.Archive:
+		return a.Reproducible(mtime), nil
+	case zip.Archive:
+		return a.Reproducible(mtime), nil
+	case gzip.Archive:
+		return a.Reproducible(mtime), nil
+	}
+	return a, nil
+}
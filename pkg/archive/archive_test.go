@@ -1,10 +1,18 @@
 package archive
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	tarArchive "github.com/goreleaser/goreleaser/pkg/archive/tar"
+	zipArchive "github.com/goreleaser/goreleaser/pkg/archive/zip"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,10 +24,8 @@ func TestArchive(t *testing.T) {
 	assert.NoError(err)
 	assert.NoError(os.Mkdir(folder+"/folder-inside", 0755))
 
-	for _, archive := range []Archive{
-		newArchive(folder, "tar.gz", t),
-		newArchive(folder, "zip", t),
-	} {
+	for _, format := range []string{"tar.gz", "tar.xz", "tar.zst", "tar", "zip"} {
+		var archive = newArchive(folder, format, t)
 		assert.NoError(archive.Add("empty.txt", empty.Name()))
 		assert.NoError(archive.Add("empty.txt", folder+"/folder-inside"))
 		assert.Error(archive.Add("dont.txt", empty.Name()+"_nope"))
@@ -27,8 +33,156 @@ func TestArchive(t *testing.T) {
 	}
 }
 
+func TestArchiveGz(t *testing.T) {
+	var assert = assert.New(t)
+	folder, err := ioutil.TempDir("", "archivetest")
+	assert.NoError(err)
+	empty, err := os.Create(folder + "/empty.txt")
+	assert.NoError(err)
+
+	var archive = newArchive(folder, "gz", t)
+	assert.NoError(archive.Add("empty.txt", empty.Name()))
+	assert.EqualError(archive.Add("other.txt", empty.Name()), "gz archives can only contain a single file")
+	assert.NoError(archive.Close())
+}
+
+func TestArchiveInvalidFormat(t *testing.T) {
+	_, err := NewFormat(ioutil.Discard, "rar", 0)
+	assert.EqualError(t, err, "invalid archive format: rar")
+}
+
+func TestNew(t *testing.T) {
+	folder, err := ioutil.TempDir("", "archivetest")
+	assert.NoError(t, err)
+	for format, kind := range map[string]interface{}{
+		"zip":    zipArchive.Archive{},
+		"tar.gz": tarArchive.Archive{},
+		"tgz":    tarArchive.Archive{},
+	} {
+		file, err := os.Create(folder + "/folder." + format)
+		assert.NoError(t, err)
+		var archive = New(file)
+		assert.IsType(t, kind, archive)
+		assert.NoError(t, archive.Close())
+	}
+}
+
+// plainArchive implements Archive only, like archives from outside this
+// package might
+type plainArchive struct {
+	added []string
+}
+
+func (a *plainArchive) Close() error { return nil }
+
+func (a *plainArchive) Add(name, path string) error {
+	a.added = append(a.added, name)
+	return nil
+}
+
+func TestAddToPlainArchive(t *testing.T) {
+	var archive = &plainArchive{}
+	assert.NoError(t, AddFile(archive, "a.txt", "a.txt", 0, time.Time{}))
+	assert.Equal(t, []string{"a.txt"}, archive.added)
+	assert.EqualError(t, AddFile(archive, "b.txt", "b.txt", 0644, time.Time{}), "*archive.plainArchive archives can't override the mode and mtime of b.txt")
+	assert.EqualError(t, AddBytes(archive, "c.txt", []byte("c"), 0644, time.Time{}), "*archive.plainArchive archives can't hold generated entries like c.txt")
+}
+
 func newArchive(folder, format string, t *testing.T) Archive {
 	file, err := os.Create(folder + "/folder." + format)
 	assert.NoError(t, err)
-	return New(file)
+	archive, err := NewFormat(file, format, 0)
+	assert.NoError(t, err)
+	return archive
+}
+
+func TestAddEntryTar(t *testing.T) {
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	archive, err := NewFormat(&buf, "tar", 0)
+	assert.NoError(t, err)
+	assert.NoError(t, AddEntry(archive, "dir", Header(os.ModeDir|0755, mtime, 0), "", nil))
+	assert.NoError(t, AddBytes(archive, "dir/file.txt", []byte("content"), 0600, mtime))
+	assert.NoError(t, AddEntry(archive, "link", Header(os.ModeSymlink|0777, mtime, 0), "dir/file.txt", nil))
+	assert.NoError(t, archive.Close())
+
+	var r = tar.NewReader(&buf)
+	next, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "dir", next.Name)
+	assert.Equal(t, byte(tar.TypeDir), next.Typeflag)
+	assert.Equal(t, int64(0755), next.Mode)
+
+	next, err = r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "dir/file.txt", next.Name)
+	assert.Equal(t, byte(tar.TypeReg), next.Typeflag)
+	assert.Equal(t, int64(0600), next.Mode)
+	assert.Equal(t, mtime, next.ModTime.UTC())
+	bts, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(bts))
+
+	next, err = r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "link", next.Name)
+	assert.Equal(t, byte(tar.TypeSymlink), next.Typeflag)
+	assert.Equal(t, "dir/file.txt", next.Linkname)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestAddEntryZip(t *testing.T) {
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	archive, err := NewFormat(&buf, "zip", 0)
+	assert.NoError(t, err)
+	assert.NoError(t, AddEntry(archive, "dir", Header(os.ModeDir|0755, mtime, 0), "", nil))
+	assert.NoError(t, AddBytes(archive, "dir/file.txt", []byte("content"), 0600, mtime))
+	assert.NoError(t, AddEntry(archive, "link", Header(os.ModeSymlink|0777, mtime, 0), "dir/file.txt", nil))
+	assert.NoError(t, archive.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, r.File, 3)
+
+	assert.Equal(t, "dir/", r.File[0].Name)
+	assert.True(t, r.File[0].Mode().IsDir())
+
+	assert.Equal(t, "dir/file.txt", r.File[1].Name)
+	assert.Equal(t, os.FileMode(0600), r.File[1].Mode())
+	assert.Equal(t, "content", zipContent(t, r.File[1]))
+
+	assert.Equal(t, "link", r.File[2].Name)
+	assert.Equal(t, os.ModeSymlink, r.File[2].Mode()&os.ModeType)
+	assert.Equal(t, "dir/file.txt", zipContent(t, r.File[2]))
+}
+
+func TestAddEntryGz(t *testing.T) {
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	archive, err := NewFormat(&buf, "gz", 0)
+	assert.NoError(t, err)
+	assert.EqualError(t, AddEntry(archive, "dir", Header(os.ModeDir|0755, mtime, 0), "", nil), "gz archives can only contain a single file")
+	assert.NoError(t, AddBytes(archive, "file.txt", []byte("content"), 0644, mtime))
+	assert.EqualError(t, AddBytes(archive, "other.txt", []byte("content"), 0644, mtime), "gz archives can only contain a single file")
+	assert.NoError(t, archive.Close())
+
+	r, err := gzip.NewReader(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "file.txt", r.Name)
+	assert.Equal(t, mtime, r.ModTime.UTC())
+	bts, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(bts))
+}
+
+func zipContent(t *testing.T, f *zip.File) string {
+	rc, err := f.Open()
+	assert.NoError(t, err)
+	defer rc.Close() // nolint: errcheck
+	bts, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	return string(bts)
 }
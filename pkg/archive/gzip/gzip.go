@@ -0,0 +1,110 @@
+// Package gzip implements the Archive interface providing gz compression of
+// a single file.
+package gzip
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrSingleFile happens when more than one file is added to a gz archive
+var ErrSingleFile = errors.New("gz archives can only contain a single file")
+
+// Archive as gz
+type Archive struct {
+	gw    *gzip.Writer
+	added *bool
+	mtime *time.Time
+}
+
+// Close all closeables
+func (a Archive) Close() error {
+	return a.gw.Close()
+}
+
+// New gz archive
+func New(target io.Writer) Archive {
+	a, _ := NewWithLevel(target, 0)
+	return a
+}
+
+// NewWithLevel gz archive with the given compression level, from 1 to 9.
+// Zero means the default level.
+func NewWithLevel(target io.Writer, level int) (Archive, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	gw, err := gzip.NewWriterLevel(target, level)
+	if err != nil {
+		return Archive{}, err
+	}
+	return Archive{
+		gw:    gw,
+		added: new(bool),
+	}, nil
+}
+
+// Reproducible returns a copy of the archive that sets the given mtime in
+// the gzip header instead of the one of the file.
+func (a Archive) Reproducible(mtime time.Time) Archive {
+	a.mtime = &mtime
+	return a
+}
+
+// Add file to the archive. Only a single regular file can be added, its
+// name and mtime are kept in the gzip header.
+func (a Archive) Add(name, path string) error {
+	return a.AddFile(name, path, 0, time.Time{})
+}
+
+// AddFile adds the file to the archive like Add, using the given mtime
+// instead of the one of the file unless it is zero. The mode is ignored, as
+// gzip headers have no room for it.
+func (a Archive) AddFile(name, path string, mode os.FileMode, mtime time.Time) error {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return err
+	}
+	defer file.Close() // nolint: errcheck
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return ErrSingleFile
+	}
+	if mtime.IsZero() {
+		mtime = a.modTime(info)
+	}
+	return a.write(name, mtime, file)
+}
+
+// AddEntry adds a regular file with the given header info to the archive,
+// reading its content from r. Directories and symlinks can't be added.
+func (a Archive) AddEntry(name string, info os.FileInfo, link string, r io.Reader) error {
+	if !info.Mode().IsRegular() {
+		return ErrSingleFile
+	}
+	return a.write(name, a.modTime(info), r)
+}
+
+func (a Archive) modTime(info os.FileInfo) time.Time {
+	if a.mtime != nil {
+		return *a.mtime
+	}
+	return info.ModTime()
+}
+
+func (a Archive) write(name string, mtime time.Time, r io.Reader) error {
+	if *a.added {
+		return ErrSingleFile
+	}
+	*a.added = true
+	a.gw.Header.Name = name
+	a.gw.Header.ModTime = mtime
+	_, err := io.Copy(a.gw, r)
+	return err
+}
@@ -0,0 +1,46 @@
+package gzip
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzFile(t *testing.T) {
+	var assert = assert.New(t)
+	tmp, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	f, err := os.Create(filepath.Join(tmp, "test.gz"))
+	assert.NoError(err)
+	defer f.Close() // nolint: errcheck
+	archive, err := NewWithLevel(f, 9)
+	assert.NoError(err)
+
+	assert.Error(archive.Add("nope.txt", "../testdata/nope.txt"))
+	assert.Equal(ErrSingleFile, archive.Add("sub1", "../testdata/sub1"))
+	assert.NoError(archive.Add("sub1/sub2/subfoo.txt", "../testdata/sub1/sub2/subfoo.txt"))
+	assert.Equal(ErrSingleFile, archive.Add("foo.txt", "../testdata/foo.txt"))
+	assert.NoError(archive.Close())
+	assert.NoError(f.Close())
+
+	f, err = os.Open(f.Name())
+	assert.NoError(err)
+	defer f.Close() // nolint: errcheck
+	gr, err := gzip.NewReader(f)
+	assert.NoError(err)
+	assert.Equal("sub1/sub2/subfoo.txt", gr.Name)
+	bts, err := ioutil.ReadAll(gr)
+	assert.NoError(err)
+	expected, err := ioutil.ReadFile("../testdata/sub1/sub2/subfoo.txt")
+	assert.NoError(err)
+	assert.Equal(expected, bts)
+}
+
+func TestInvalidLevel(t *testing.T) {
+	_, err := NewWithLevel(ioutil.Discard, 42)
+	assert.EqualError(t, err, "gzip: invalid compression level: 42")
+}
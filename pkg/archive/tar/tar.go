@@ -1,5 +1,5 @@
-// Package tar implements the Archive interface providing tar.gz archiving
-// and compression.
+// Package tar implements the Archive interface providing tar archiving,
+// either plain or compressed with gzip, xz or zstd.
 package tar
 
 import (
@@ -7,12 +7,17 @@ import (
 	"compress/gzip"
 	"io"
 	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
-// Archive as tar.gz
+// Archive as tar, optionally compressed
 type Archive struct {
-	gw *gzip.Writer
-	tw *tar.Writer
+	cw    io.WriteCloser
+	tw    *tar.Writer
+	mtime *time.Time
 }
 
 // Close all closeables
@@ -20,21 +25,99 @@ func (a Archive) Close() error {
 	if err := a.tw.Close(); err != nil {
 		return err
 	}
-	return a.gw.Close()
+	if a.cw == nil {
+		return nil
+	}
+	return a.cw.Close()
 }
 
 // New tar.gz archive
 func New(target io.Writer) Archive {
 	gw := gzip.NewWriter(target)
-	tw := tar.NewWriter(gw)
+	return newArchive(target, gw)
+}
+
+// NewPlain uncompressed tar archive
+func NewPlain(target io.Writer) Archive {
+	return newArchive(target, nil)
+}
+
+// NewGz tar.gz archive with the given compression level, from 1 to 9.
+// Zero means the default level.
+func NewGz(target io.Writer, level int) (Archive, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	gw, err := gzip.NewWriterLevel(target, level)
+	if err != nil {
+		return Archive{}, err
+	}
+	return newArchive(target, gw), nil
+}
+
+// NewXz tar.xz archive with the given compression level, from 1 to 9,
+// following the dictionary sizes of the xz command presets.
+// Zero means the default level.
+func NewXz(target io.Writer, level int) (Archive, error) {
+	var cfg xz.WriterConfig
+	if level != 0 {
+		dictCap, err := xzDictCap(level)
+		if err != nil {
+			return Archive{}, err
+		}
+		cfg.DictCap = dictCap
+	}
+	xw, err := cfg.NewWriter(target)
+	if err != nil {
+		return Archive{}, err
+	}
+	return newArchive(target, xw), nil
+}
+
+// NewZst tar.zst archive with the given compression level, from 1 to 22,
+// mapped to the closest level the encoder supports.
+// Zero means the default level.
+func NewZst(target io.Writer, level int) (Archive, error) {
+	var opts = []zstd.EOption{
+		// a single goroutine keeps the output stable across machines
+		zstd.WithEncoderConcurrency(1),
+	}
+	if level != 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+	zw, err := zstd.NewWriter(target, opts...)
+	if err != nil {
+		return Archive{}, err
+	}
+	return newArchive(target, zw), nil
+}
+
+// Reproducible returns a copy of the archive that writes entries with
+// normalized metadata: the given mtime, no owner and either 0644 or 0755
+// permissions, so the same files always produce the same archive.
+func (a Archive) Reproducible(mtime time.Time) Archive {
+	a.mtime = &mtime
+	return a
+}
+
+func newArchive(target io.Writer, cw io.WriteCloser) Archive {
+	if cw == nil {
+		return Archive{tw: tar.NewWriter(target)}
+	}
 	return Archive{
-		gw: gw,
-		tw: tw,
+		cw: cw,
+		tw: tar.NewWriter(cw),
 	}
 }
 
 // Add file to the archive
 func (a Archive) Add(name, path string) error {
+	return a.AddFile(name, path, 0, time.Time{})
+}
+
+// AddFile adds the file to the archive, using the given mode and mtime
+// instead of the ones of the file unless they are zero.
+func (a Archive) AddFile(name, path string, mode os.FileMode, mtime time.Time) error {
 	file, err := os.Open(path) // #nosec
 	if err != nil {
 		return err
@@ -49,12 +132,57 @@ func (a Archive) Add(name, path string) error {
 		return err
 	}
 	header.Name = name
-	if err = a.tw.WriteHeader(header); err != nil {
+	if a.mtime != nil {
+		normalize(header, *a.mtime)
+	}
+	if mode != 0 {
+		header.Mode = int64(mode.Perm())
+	}
+	if !mtime.IsZero() {
+		header.ModTime = mtime
+	}
+	return a.write(header, file)
+}
+
+// AddEntry adds an entry with the given header info to the archive. The
+// content of regular files is read from r, while directories and symlinks,
+// which point to link, have none.
+func (a Archive) AddEntry(name string, info os.FileInfo, link string, r io.Reader) error {
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if a.mtime != nil {
+		normalize(header, *a.mtime)
+	}
+	return a.write(header, r)
+}
+
+func (a Archive) write(header *tar.Header, r io.Reader) error {
+	if err := a.tw.WriteHeader(header); err != nil {
 		return err
 	}
-	if info.IsDir() {
+	if header.Typeflag != tar.TypeReg {
 		return nil
 	}
-	_, err = io.Copy(a.tw, file)
+	_, err := io.Copy(a.tw, r)
 	return err
 }
+
+func normalize(header *tar.Header, mtime time.Time) {
+	header.ModTime = mtime.UTC().Truncate(time.Second)
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.Uid = 0
+	header.Gid = 0
+	header.Uname = ""
+	header.Gname = ""
+	if header.Typeflag == tar.TypeSymlink {
+		header.Mode = 0777
+	} else if header.Typeflag == tar.TypeDir || header.Mode&0111 != 0 {
+		header.Mode = 0755
+	} else {
+		header.Mode = 0644
+	}
+}
@@ -2,14 +2,18 @@ package tar
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
 )
 
 func TestTarGzFile(t *testing.T) {
@@ -65,3 +69,120 @@ func TestTarGzFile(t *testing.T) {
 		"sub1/sub2/subfoo.txt",
 	}, paths)
 }
+
+func TestCompressions(t *testing.T) {
+	for name, tt := range map[string]struct {
+		create func(w io.Writer) (Archive, error)
+		reader func(r io.Reader) (io.Reader, error)
+	}{
+		"tar": {
+			create: func(w io.Writer) (Archive, error) { return NewPlain(w), nil },
+			reader: func(r io.Reader) (io.Reader, error) { return r, nil },
+		},
+		"tar.gz": {
+			create: func(w io.Writer) (Archive, error) { return NewGz(w, 9) },
+			reader: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+		"tar.xz": {
+			create: func(w io.Writer) (Archive, error) { return NewXz(w, 9) },
+			reader: func(r io.Reader) (io.Reader, error) { return xz.NewReader(r) },
+		},
+		"tar.zst": {
+			create: func(w io.Writer) (Archive, error) { return NewZst(w, 19) },
+			reader: func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			archive, err := tt.create(&buf)
+			assert.NoError(t, err)
+			assert.NoError(t, archive.Add("foo.txt", "../testdata/foo.txt"))
+			assert.NoError(t, archive.Add("sub1/bar.txt", "../testdata/sub1/bar.txt"))
+			assert.NoError(t, archive.Close())
+
+			r, err := tt.reader(&buf)
+			assert.NoError(t, err)
+			var paths []string
+			tr := tar.NewReader(r)
+			for {
+				next, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				paths = append(paths, next.Name)
+			}
+			assert.Equal(t, []string{"foo.txt", "sub1/bar.txt"}, paths)
+		})
+	}
+}
+
+func TestInvalidLevels(t *testing.T) {
+	_, err := NewGz(ioutil.Discard, 42)
+	assert.EqualError(t, err, "gzip: invalid compression level: 42")
+	_, err = NewXz(ioutil.Discard, 42)
+	assert.EqualError(t, err, "invalid xz compression level: 42")
+}
+
+func TestReproducible(t *testing.T) {
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var create = func(now time.Time) []byte {
+		// touch the files so their mtimes differ between runs
+		assert.NoError(t, os.Chtimes("../testdata/foo.txt", now, now))
+		assert.NoError(t, os.Chtimes("../testdata/sub1/executable", now, now))
+		var buf bytes.Buffer
+		archive := NewPlain(&buf).Reproducible(mtime)
+		assert.NoError(t, archive.Add("foo.txt", "../testdata/foo.txt"))
+		assert.NoError(t, archive.Add("sub1", "../testdata/sub1"))
+		assert.NoError(t, archive.Add("sub1/executable", "../testdata/sub1/executable"))
+		assert.NoError(t, archive.Close())
+		return buf.Bytes()
+	}
+	var bts = create(time.Now())
+	assert.Equal(t, bts, create(time.Now().Add(time.Hour)))
+
+	var r = tar.NewReader(bytes.NewReader(bts))
+	var modes = map[string]int64{}
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, mtime, next.ModTime.UTC())
+		assert.Equal(t, 0, next.Uid)
+		assert.Equal(t, 0, next.Gid)
+		assert.Empty(t, next.Uname)
+		assert.Empty(t, next.Gname)
+		modes[next.Name] = next.Mode
+	}
+	assert.Equal(t, map[string]int64{
+		"foo.txt":         0644,
+		"sub1":            0755,
+		"sub1/executable": 0755,
+	}, modes)
+}
+
+func TestAddFile(t *testing.T) {
+	var mtime = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	archive := NewPlain(&buf)
+	assert.NoError(t, archive.AddFile("foo.txt", "../testdata/foo.txt", 0600, mtime))
+	assert.NoError(t, archive.AddFile("bar.txt", "../testdata/sub1/bar.txt", 0, time.Time{}))
+	assert.NoError(t, archive.Close())
+
+	var r = tar.NewReader(&buf)
+	next, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "foo.txt", next.Name)
+	assert.Equal(t, int64(0600), next.Mode)
+	assert.Equal(t, mtime, next.ModTime.UTC())
+
+	info, err := os.Stat("../testdata/sub1/bar.txt")
+	assert.NoError(t, err)
+	next, err = r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "bar.txt", next.Name)
+	assert.Equal(t, int64(info.Mode().Perm()), next.Mode)
+	assert.Equal(t, info.ModTime().Unix(), next.ModTime.Unix())
+}
@@ -0,0 +1,25 @@
+package tar
+
+import "fmt"
+
+// xzDictCap returns the dictionary size used by the given xz preset level
+func xzDictCap(level int) (int, error) {
+	const mib = 1024 * 1024
+	switch level {
+	case 1:
+		return 1 * mib, nil
+	case 2:
+		return 2 * mib, nil
+	case 3, 4:
+		return 4 * mib, nil
+	case 5, 6:
+		return 8 * mib, nil
+	case 7:
+		return 16 * mib, nil
+	case 8:
+		return 32 * mib, nil
+	case 9:
+		return 64 * mib, nil
+	}
+	return 0, fmt.Errorf("invalid xz compression level: %d", level)
+}
@@ -4,13 +4,18 @@ package zip
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 )
 
 // Archive zip struct
 type Archive struct {
-	z *zip.Writer
+	z     *zip.Writer
+	mtime *time.Time
 }
 
 // Close all closeables
@@ -25,19 +30,50 @@ func New(target io.Writer) Archive {
 	}
 }
 
+// NewWithLevel zip archive with the given deflate compression level, from 1
+// to 9. Zero means the default level.
+func NewWithLevel(target io.Writer, level int) (Archive, error) {
+	if level == 0 {
+		return New(target), nil
+	}
+	// validates the level upfront, as the compressor can't return errors
+	if _, err := flate.NewWriter(ioutil.Discard, level); err != nil {
+		return Archive{}, err
+	}
+	var z = zip.NewWriter(target)
+	z.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, level)
+	})
+	return Archive{z: z}, nil
+}
+
+// Reproducible returns a copy of the archive that writes entries with
+// normalized metadata: the given mtime and either 0644 or 0755 permissions,
+// so the same files always produce the same archive.
+func (a Archive) Reproducible(mtime time.Time) Archive {
+	a.mtime = &mtime
+	return a
+}
+
 // Add a file to the zip archive
-func (a Archive) Add(name, path string) (err error) {
+func (a Archive) Add(name, path string) error {
+	return a.AddFile(name, path, 0, time.Time{})
+}
+
+// AddFile adds a file to the zip archive, using the given mode and mtime
+// instead of the ones of the file unless they are zero.
+func (a Archive) AddFile(name, path string, mode os.FileMode, mtime time.Time) error {
 	file, err := os.Open(path) // #nosec
 	if err != nil {
-		return
+		return err
 	}
 	defer file.Close() // nolint: errcheck
 	info, err := file.Stat()
 	if err != nil {
-		return
+		return err
 	}
 	if info.IsDir() {
-		return
+		return nil
 	}
 	header, err := zip.FileInfoHeader(info)
 	if err != nil {
@@ -45,6 +81,15 @@ func (a Archive) Add(name, path string) (err error) {
 	}
 	header.Name = name
 	header.Method = zip.Deflate
+	if a.mtime != nil {
+		normalize(header, *a.mtime)
+	}
+	if mode != 0 {
+		header.SetMode(mode.Perm())
+	}
+	if !mtime.IsZero() {
+		header.Modified = mtime
+	}
 	w, err := a.z.CreateHeader(header)
 	if err != nil {
 		return err
@@ -52,3 +97,48 @@ func (a Archive) Add(name, path string) (err error) {
 	_, err = io.Copy(w, file)
 	return err
 }
+
+// AddEntry adds an entry with the given header info to the zip archive. The
+// content of regular files is read from r, directories have none and
+// symlinks store link as their content, as zip tools expect.
+func (a Archive) AddEntry(name string, info os.FileInfo, link string, r io.Reader) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	if info.IsDir() {
+		header.Name = strings.TrimSuffix(name, "/") + "/"
+		header.Method = zip.Store
+	}
+	if a.mtime != nil {
+		normalize(header, *a.mtime)
+	}
+	w, err := a.z.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		_, err = io.WriteString(w, link)
+	case info.Mode().IsRegular():
+		_, err = io.Copy(w, r)
+	}
+	return err
+}
+
+func normalize(header *zip.FileHeader, mtime time.Time) {
+	header.Modified = mtime.UTC().Truncate(time.Second)
+	var mode = header.Mode()
+	switch {
+	case mode&os.ModeSymlink != 0:
+		header.SetMode(os.ModeSymlink | 0777)
+	case mode.IsDir():
+		header.SetMode(os.ModeDir | 0755)
+	case mode&0111 != 0:
+		header.SetMode(0755)
+	default:
+		header.SetMode(0644)
+	}
+}
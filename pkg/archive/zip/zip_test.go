@@ -2,11 +2,13 @@ package zip
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +59,45 @@ func TestZipFile(t *testing.T) {
 		"sub1/sub2/subfoo.txt",
 	}, paths)
 }
+
+func TestZipLevel(t *testing.T) {
+	var assert = assert.New(t)
+	var buf bytes.Buffer
+	archive, err := NewWithLevel(&buf, 9)
+	assert.NoError(err)
+	assert.NoError(archive.Add("foo.txt", "../testdata/foo.txt"))
+	assert.NoError(archive.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(err)
+	assert.Len(r.File, 1)
+	assert.Equal("foo.txt", r.File[0].Name)
+
+	_, err = NewWithLevel(&buf, 42)
+	assert.EqualError(err, "flate: invalid compression level 42: want value in range [-2, 9]")
+}
+
+func TestZipReproducible(t *testing.T) {
+	var assert = assert.New(t)
+	var mtime = time.Date(2019, 1, 2, 3, 4, 6, 0, time.UTC)
+	var create = func(now time.Time) []byte {
+		// touch the files so their mtimes differ between runs
+		assert.NoError(os.Chtimes("../testdata/foo.txt", now, now))
+		assert.NoError(os.Chtimes("../testdata/sub1/executable", now, now))
+		var buf bytes.Buffer
+		archive := New(&buf).Reproducible(mtime)
+		assert.NoError(archive.Add("foo.txt", "../testdata/foo.txt"))
+		assert.NoError(archive.Add("sub1/executable", "../testdata/sub1/executable"))
+		assert.NoError(archive.Close())
+		return buf.Bytes()
+	}
+	var bts = create(time.Now())
+	assert.Equal(bts, create(time.Now().Add(time.Hour)))
+
+	r, err := zip.NewReader(bytes.NewReader(bts), int64(len(bts)))
+	assert.NoError(err)
+	assert.Len(r.File, 2)
+	assert.Equal(mtime, r.File[0].Modified.UTC())
+	assert.Equal(os.FileMode(0644), r.File[0].Mode())
+	assert.Equal(os.FileMode(0755), r.File[1].Mode())
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/pkg/jsonschema"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -19,6 +20,13 @@ type GitHubURLs struct {
 	Download string `yaml:"download,omitempty"`
 }
 
+// GitLabURLs holds the URLs to be used when using gitlab ce/enterprise
+type GitLabURLs struct {
+	API                string `yaml:"api,omitempty"`
+	Download           string `yaml:"download,omitempty"`
+	UsePackageRegistry bool   `yaml:"use_package_registry,omitempty"`
+}
+
 // Repo represents any kind of repo (github, gitlab, etc)
 type Repo struct {
 	Owner string `yaml:",omitempty"`
@@ -36,7 +44,9 @@ func (r Repo) String() string {
 // Homebrew contains the brew section
 type Homebrew struct {
 	Name             string       `yaml:",omitempty"`
+	IDs              []string     `yaml:"ids,omitempty"`
 	GitHub           Repo         `yaml:",omitempty"`
+	GitLab           Repo         `yaml:",omitempty"`
 	CommitAuthor     CommitAuthor `yaml:"commit_author,omitempty"`
 	Folder           string       `yaml:",omitempty"`
 	Caveats          string       `yaml:",omitempty"`
@@ -58,6 +68,7 @@ type Homebrew struct {
 // Scoop contains the scoop.sh section
 type Scoop struct {
 	Name         string       `yaml:",omitempty"`
+	IDs          []string     `yaml:"ids,omitempty"`
 	Bucket       Repo         `yaml:",omitempty"`
 	CommitAuthor CommitAuthor `yaml:"commit_author,omitempty"`
 	Homepage     string       `yaml:",omitempty"`
@@ -69,8 +80,8 @@ type Scoop struct {
 
 // CommitAuthor is the author of a Git commit
 type CommitAuthor struct {
-	Name  string `yaml:",omitempty"`
-	Email string `yaml:",omitempty"`
+	Name  string `yaml:",omitempty" jsonschema:"default=goreleaserbot"`
+	Email string `yaml:",omitempty" jsonschema:"default=goreleaser@carlosbecker.com"`
 }
 
 // Hooks define actions to run before and/or after something
@@ -102,6 +113,11 @@ func (a *StringArray) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// JSONSchema describes StringArray as either a string or a list of strings
+func (StringArray) JSONSchema() *jsonschema.Schema {
+	return stringOrArraySchema()
+}
+
 // FlagArray is a wrapper for an array of strings
 type FlagArray []string
 
@@ -120,48 +136,121 @@ func (a *FlagArray) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// JSONSchema describes FlagArray as either a string or a list of strings
+func (FlagArray) JSONSchema() *jsonschema.Schema {
+	return stringOrArraySchema()
+}
+
+func stringOrArraySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
 // Build contains the build configuration section
 type Build struct {
-	Goos     []string       `yaml:",omitempty"`
-	Goarch   []string       `yaml:",omitempty"`
-	Goarm    []string       `yaml:",omitempty"`
+	ID       string         `yaml:"id,omitempty"`
+	Goos     []string       `yaml:",omitempty" jsonschema:"default=linux,default=darwin"`
+	Goarch   []string       `yaml:",omitempty" jsonschema:"default=amd64,default=386"`
+	Goarm    []string       `yaml:",omitempty" jsonschema:"default=6"`
 	Targets  []string       `yaml:",omitempty"`
 	Ignore   []IgnoredBuild `yaml:",omitempty"`
-	Main     string         `yaml:",omitempty"`
-	Ldflags  StringArray    `yaml:",omitempty"`
+	Main     string         `yaml:",omitempty" jsonschema:"default=."`
+	Ldflags  StringArray    `yaml:",omitempty" jsonschema:"default=-s -w -X main.version={{.Version}} -X main.commit={{.Commit}} -X main.date={{.Date}}"`
 	Flags    FlagArray      `yaml:",omitempty"`
 	Binary   string         `yaml:",omitempty"`
 	Hooks    Hooks          `yaml:",omitempty"`
 	Env      []string       `yaml:",omitempty"`
-	Lang     string         `yaml:",omitempty"`
+	Lang     string         `yaml:",omitempty" jsonschema:"enum=go,default=go"`
 	Asmflags StringArray    `yaml:",omitempty"`
 	Gcflags  StringArray    `yaml:",omitempty"`
 }
 
 // FormatOverride is used to specify a custom format for a specific GOOS.
 type FormatOverride struct {
-	Goos   string `yaml:",omitempty"`
-	Format string `yaml:",omitempty"`
+	Goos             string `yaml:",omitempty"`
+	Format           string `yaml:",omitempty" jsonschema:"enum=tar.gz,enum=tar.xz,enum=tar.zst,enum=tar,enum=gz,enum=zip,enum=binary"`
+	CompressionLevel int    `yaml:"compression_level,omitempty"`
 }
 
 // Archive config used for the archive
 type Archive struct {
-	NameTemplate string            `yaml:"name_template,omitempty"`
+	ID           string            `yaml:"id,omitempty" jsonschema:"default=default"`
+	NameTemplate string            `yaml:"name_template,omitempty" jsonschema:"default={{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}{{ if .Arm }}v{{ .Arm }}{{ end }}"`
 	Replacements map[string]string `yaml:",omitempty"`
 
-	Format          string           `yaml:",omitempty"`
-	FormatOverrides []FormatOverride `yaml:"format_overrides,omitempty"`
-	WrapInDirectory string           `yaml:"wrap_in_directory,omitempty"`
-	Files           []string         `yaml:",omitempty"`
+	Format           string           `yaml:",omitempty" jsonschema:"enum=tar.gz,enum=tar.xz,enum=tar.zst,enum=tar,enum=gz,enum=zip,enum=binary,default=tar.gz"`
+	CompressionLevel int              `yaml:"compression_level,omitempty"`
+	FormatOverrides  []FormatOverride `yaml:"format_overrides,omitempty"`
+	WrapInDirectory  string           `yaml:"wrap_in_directory,omitempty" jsonschema:"type=string,type=boolean"`
+	Files            []File           `yaml:",omitempty"`
+	TemplatedFiles   []TemplatedFile  `yaml:"templated_files,omitempty"`
+	IDs              []string         `yaml:"ids,omitempty"`
+	Reproducible     bool             `yaml:",omitempty"`
+}
+
+// File is a file or glob of files to add to an archive
+type File struct {
+	Source      string      `yaml:"src,omitempty"`
+	Destination string      `yaml:"dst,omitempty"`
+	StripParent bool        `yaml:"strip_parent,omitempty"`
+	Mode        os.FileMode `yaml:",omitempty"`
+	MTime       string      `yaml:"mtime,omitempty"`
+	Goos        string      `yaml:",omitempty"`
+	Goarch      string      `yaml:",omitempty"`
+}
+
+// TemplatedFile is a file rendered through the template engine before being
+// added to an archive, package or docker image
+type TemplatedFile struct {
+	Source      string      `yaml:"src,omitempty"`
+	Destination string      `yaml:"dst,omitempty"`
+	Mode        os.FileMode `yaml:",omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that accepts plain globs as files
+func (f *File) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type file File
+	var str string
+	if err := unmarshal(&str); err == nil {
+		*f = File{Source: str}
+		return nil
+	}
+	var ff file
+	if err := unmarshal(&ff); err != nil {
+		return err
+	}
+	*f = File(ff)
+	return nil
+}
+
+// JSONSchema describes File as either a glob or a file object
+func (File) JSONSchema() *jsonschema.Schema {
+	type file File
+	var schema = jsonschema.Reflect(&file{})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string"},
+			schema.Definitions["file"],
+		},
+	}
 }
 
-// Release config used for the GitHub release
+// Release config used for the GitHub or GitLab release
 type Release struct {
-	GitHub       Repo   `yaml:",omitempty"`
-	Draft        bool   `yaml:",omitempty"`
-	Disable      bool   `yaml:",omitempty"`
-	Prerelease   string `yaml:",omitempty"`
-	NameTemplate string `yaml:"name_template,omitempty"`
+	GitHub       Repo     `yaml:",omitempty"`
+	GitLab       Repo     `yaml:",omitempty"`
+	Draft        bool     `yaml:",omitempty"`
+	Disable      bool     `yaml:",omitempty"`
+	Prerelease   string   `yaml:",omitempty"`
+	NameTemplate string   `yaml:"name_template,omitempty" jsonschema:"default={{.Tag}}"`
+	IDs          []string `yaml:"ids,omitempty"`
+	Header       string   `yaml:",omitempty"`
+	Footer       string   `yaml:",omitempty"`
+	BodyTemplate string   `yaml:"body_template,omitempty"`
 }
 
 // NFPM config
@@ -169,13 +258,15 @@ type NFPM struct {
 	NFPMOverridables `yaml:",inline"`
 	Overrides        map[string]NFPMOverridables `yaml:"overrides,omitempty"`
 
-	Formats     []string `yaml:",omitempty"`
-	Vendor      string   `yaml:",omitempty"`
-	Homepage    string   `yaml:",omitempty"`
-	Maintainer  string   `yaml:",omitempty"`
-	Description string   `yaml:",omitempty"`
-	License     string   `yaml:",omitempty"`
-	Bindir      string   `yaml:",omitempty"`
+	Formats        []string        `yaml:",omitempty" jsonschema:"enum=deb,enum=rpm"`
+	IDs            []string        `yaml:"ids,omitempty"`
+	Vendor         string          `yaml:",omitempty"`
+	Homepage       string          `yaml:",omitempty"`
+	Maintainer     string          `yaml:",omitempty"`
+	Description    string          `yaml:",omitempty"`
+	License        string          `yaml:",omitempty"`
+	Bindir         string          `yaml:",omitempty" jsonschema:"default=/usr/local/bin"`
+	TemplatedFiles []TemplatedFile `yaml:"templated_files,omitempty"`
 }
 
 // NFPMScripts is used to specify maintainer scripts
@@ -202,10 +293,20 @@ type NFPMOverridables struct {
 
 // Sign config
 type Sign struct {
-	Cmd       string   `yaml:"cmd,omitempty"`
-	Args      []string `yaml:"args,omitempty"`
-	Signature string   `yaml:"signature,omitempty"`
-	Artifacts string   `yaml:"artifacts,omitempty"`
+	ID             string   `yaml:"id,omitempty" jsonschema:"default=default"`
+	Signer         string   `yaml:"signer,omitempty" jsonschema:"enum=cmd,enum=openpgp,enum=minisign,default=cmd"`
+	Cmd            string   `yaml:"cmd,omitempty" jsonschema:"default=gpg"`
+	Args           []string `yaml:"args,omitempty" jsonschema:"default=--output,default=$signature,default=--detach-sig,default=$artifact"`
+	Env            []string `yaml:"env,omitempty"`
+	Signature      string   `yaml:"signature,omitempty" jsonschema:"default=${artifact}.sig"`
+	Artifacts      string   `yaml:"artifacts,omitempty" jsonschema:"enum=none,enum=checksum,enum=source,enum=archive,enum=binary,enum=package,enum=all,default=none"`
+	IDs            []string `yaml:"ids,omitempty"`
+	Glob           string   `yaml:"glob,omitempty"`
+	Key            string   `yaml:"key,omitempty"`
+	KeyFile        string   `yaml:"key_file,omitempty"`
+	Passphrase     string   `yaml:"passphrase,omitempty"`
+	PassphraseFile string   `yaml:"passphrase_file,omitempty"`
+	Armor          bool     `yaml:"armor,omitempty"`
 }
 
 // SnapcraftAppMetadata for the binaries that will be in the snap package
@@ -220,6 +321,7 @@ type Snapcraft struct {
 	NameTemplate string            `yaml:"name_template,omitempty"`
 	Replacements map[string]string `yaml:",omitempty"`
 	Publish      bool              `yaml:",omitempty"`
+	IDs          []string          `yaml:"ids,omitempty"`
 
 	Name        string                          `yaml:",omitempty"`
 	Summary     string                          `yaml:",omitempty"`
@@ -231,28 +333,40 @@ type Snapcraft struct {
 
 // Snapshot config
 type Snapshot struct {
-	NameTemplate string `yaml:"name_template,omitempty"`
+	NameTemplate string `yaml:"name_template,omitempty" jsonschema:"default=SNAPSHOT-{{ .ShortCommit }}"`
+}
+
+// Source config
+type Source struct {
+	Enabled      bool     `yaml:",omitempty"`
+	NameTemplate string   `yaml:"name_template,omitempty" jsonschema:"default={{ .ProjectName }}_{{ .Version }}_source"`
+	Vendor       bool     `yaml:",omitempty"`
+	Files        []string `yaml:",omitempty"`
 }
 
 // Checksum config
 type Checksum struct {
-	NameTemplate string `yaml:"name_template,omitempty"`
+	NameTemplate string `yaml:"name_template,omitempty" jsonschema:"default={{ .ProjectName }}_{{ .Version }}_checksums.txt"`
+	Algorithm    string `yaml:",omitempty" jsonschema:"enum=sha1,enum=sha256,enum=sha512,enum=md5,enum=blake2b,enum=crc32,default=sha256"`
+	Sidecar      bool   `yaml:",omitempty"`
 }
 
 // Docker image config
 type Docker struct {
-	Binary             string   `yaml:",omitempty"`
-	Binaries           []string `yaml:",omitempty"`
-	Goos               string   `yaml:",omitempty"`
-	Goarch             string   `yaml:",omitempty"`
-	Goarm              string   `yaml:",omitempty"`
-	Image              string   `yaml:",omitempty"`
-	Dockerfile         string   `yaml:",omitempty"`
-	ImageTemplates     []string `yaml:"image_templates,omitempty"`
-	SkipPush           bool     `yaml:"skip_push,omitempty"`
-	TagTemplates       []string `yaml:"tag_templates,omitempty"`
-	Files              []string `yaml:"extra_files,omitempty"`
-	BuildFlagTemplates []string `yaml:"build_flag_templates,omitempty"`
+	Binary             string          `yaml:",omitempty"`
+	Binaries           []string        `yaml:",omitempty"`
+	Goos               string          `yaml:",omitempty" jsonschema:"default=linux"`
+	Goarch             string          `yaml:",omitempty" jsonschema:"default=amd64"`
+	Goarm              string          `yaml:",omitempty"`
+	Image              string          `yaml:",omitempty"`
+	Dockerfile         string          `yaml:",omitempty"`
+	ImageTemplates     []string        `yaml:"image_templates,omitempty"`
+	SkipPush           bool            `yaml:"skip_push,omitempty"`
+	TagTemplates       []string        `yaml:"tag_templates,omitempty"`
+	Files              []string        `yaml:"extra_files,omitempty"`
+	TemplatedFiles     []TemplatedFile `yaml:"templated_files,omitempty"`
+	BuildFlagTemplates []string        `yaml:"build_flag_templates,omitempty"`
+	IDs                []string        `yaml:"ids,omitempty"`
 }
 
 // Filters config
@@ -260,16 +374,47 @@ type Filters struct {
 	Exclude []string `yaml:",omitempty"`
 }
 
+// ChangelogGroup config, the entries whose message matches the regexp, or
+// pull requests with one of the labels, are listed under the title
+type ChangelogGroup struct {
+	Title  string   `yaml:",omitempty"`
+	Regexp string   `yaml:",omitempty"`
+	Labels []string `yaml:",omitempty"`
+	Order  int      `yaml:",omitempty"`
+}
+
+// ChangelogContributors config
+type ChangelogContributors struct {
+	Enabled bool     `yaml:",omitempty"`
+	Title   string   `yaml:",omitempty" jsonschema:"default=Contributors"`
+	Mapping string   `yaml:",omitempty"`
+	Exclude []string `yaml:",omitempty"`
+}
+
+// ChangelogFile config, a Keep a Changelog file in the repository the release
+// notes are prepended to
+type ChangelogFile struct {
+	Path         string       `yaml:",omitempty"`
+	Commit       bool         `yaml:",omitempty"`
+	CommitAuthor CommitAuthor `yaml:"commit_author,omitempty"`
+}
+
 // Changelog Config
 type Changelog struct {
-	Filters Filters `yaml:",omitempty"`
-	Sort    string  `yaml:",omitempty"`
+	Filters      Filters               `yaml:",omitempty"`
+	Sort         string                `yaml:",omitempty" jsonschema:"enum=,enum=asc,enum=desc"`
+	Groups       []ChangelogGroup      `yaml:",omitempty"`
+	Template     string                `yaml:",omitempty"`
+	Source       string                `yaml:",omitempty" jsonschema:"enum=git,enum=github,enum=gitlab,default=git"`
+	Contributors ChangelogContributors `yaml:",omitempty"`
+	File         ChangelogFile         `yaml:",omitempty"`
 }
 
 // EnvFiles holds paths to files that contains environment variables
 // values like the github token for example
 type EnvFiles struct {
-	GitHubToken string `yaml:"github_token,omitempty"`
+	GitHubToken string `yaml:"github_token,omitempty" jsonschema:"default=~/.config/goreleaser/github_token"`
+	GitLabToken string `yaml:"gitlab_token,omitempty" jsonschema:"default=~/.config/goreleaser/gitlab_token"`
 }
 
 // Git config
@@ -284,24 +429,26 @@ type Before struct {
 
 // S3 contains s3 config
 type S3 struct {
-	Region   string
+	Region   string `jsonschema:"default=us-east-1"`
 	Bucket   string
-	Folder   string
+	Folder   string `jsonschema:"default={{ .ProjectName }}/{{ .Tag }}"`
 	Profile  string
-	Endpoint string // used for minio for example
-	ACL      string
+	Endpoint string   // used for minio for example
+	ACL      string   `jsonschema:"default=private"`
+	IDs      []string `yaml:"ids,omitempty"`
 }
 
 // Put HTTP upload configuration
 type Put struct {
-	Name           string `yaml:",omitempty"`
-	Target         string `yaml:",omitempty"`
-	Username       string `yaml:",omitempty"`
-	Mode           string `yaml:",omitempty"`
-	ChecksumHeader string `yaml:"checksum_header,omitempty"`
-	TrustedCerts   string `yaml:"trusted_certificates,omitempty"`
-	Checksum       bool   `yaml:",omitempty"`
-	Signature      bool   `yaml:",omitempty"`
+	Name           string   `yaml:",omitempty"`
+	Target         string   `yaml:",omitempty"`
+	Username       string   `yaml:",omitempty"`
+	Mode           string   `yaml:",omitempty" jsonschema:"enum=archive,enum=binary,default=archive"`
+	ChecksumHeader string   `yaml:"checksum_header,omitempty"`
+	TrustedCerts   string   `yaml:"trusted_certificates,omitempty"`
+	Checksum       bool     `yaml:",omitempty"`
+	Signature      bool     `yaml:",omitempty"`
+	IDs            []string `yaml:"ids,omitempty"`
 }
 
 // Project includes all project configuration
@@ -312,6 +459,8 @@ type Project struct {
 	Scoop         Scoop     `yaml:",omitempty"`
 	Builds        []Build   `yaml:",omitempty"`
 	Archive       Archive   `yaml:",omitempty"`
+	Archives      []Archive `yaml:",omitempty"`
+	Source        Source    `yaml:",omitempty"`
 	NFPM          NFPM      `yaml:",omitempty"`
 	Snapcraft     Snapcraft `yaml:",omitempty"`
 	Snapshot      Snapshot  `yaml:",omitempty"`
@@ -321,8 +470,9 @@ type Project struct {
 	Puts          []Put     `yaml:",omitempty"`
 	S3            []S3      `yaml:"s3,omitempty"`
 	Changelog     Changelog `yaml:",omitempty"`
-	Dist          string    `yaml:",omitempty"`
+	Dist          string    `yaml:",omitempty" jsonschema:"default=dist"`
 	Sign          Sign      `yaml:",omitempty"`
+	Signs         []Sign    `yaml:",omitempty"`
 	EnvFiles      EnvFiles  `yaml:"env_files,omitempty"`
 	Git           Git       `yaml:",omitempty"`
 	Before        Before    `yaml:",omitempty"`
@@ -332,6 +482,10 @@ type Project struct {
 
 	// should be set if using github enterprise
 	GitHubURLs GitHubURLs `yaml:"github_urls,omitempty"`
+
+	// should be set if using a self-hosted gitlab whose host can't be
+	// detected from the origin remote
+	GitLabURLs GitLabURLs `yaml:"gitlab_urls,omitempty"`
 }
 
 // Load config file
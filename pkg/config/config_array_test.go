@@ -3,6 +3,7 @@ package config
 import (
 	"testing"
 
+	"github.com/goreleaser/goreleaser/pkg/jsonschema"
 	"github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -124,3 +125,53 @@ func TestFlagArray(t *testing.T) {
 		assert.Equal(t, testCase.expected, actual)
 	}
 }
+
+func TestArraysJSONSchema(t *testing.T) {
+	for _, schema := range []*jsonschema.Schema{
+		StringArray{}.JSONSchema(),
+		FlagArray{}.JSONSchema(),
+	} {
+		assert.Len(t, schema.OneOf, 2)
+		assert.Equal(t, "string", schema.OneOf[0].Type)
+		assert.Equal(t, "array", schema.OneOf[1].Type)
+		assert.Equal(t, "string", schema.OneOf[1].Items.Type)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	var actual struct {
+		Files []File
+	}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(`files:
+- README.md
+- src: docs/*.service
+  dst: systemd/
+  strip_parent: true
+  mode: 0644
+  mtime: 2019-01-02T03:04:05Z
+  goos: linux
+  goarch: amd64
+`), &actual))
+	assert.Equal(t, []File{
+		{Source: "README.md"},
+		{
+			Source:      "docs/*.service",
+			Destination: "systemd/",
+			StripParent: true,
+			Mode:        0644,
+			MTime:       "2019-01-02T03:04:05Z",
+			Goos:        "linux",
+			Goarch:      "amd64",
+		},
+	}, actual.Files)
+
+	assert.Error(t, yaml.UnmarshalStrict([]byte("files: [{source: foo}]"), &actual))
+}
+
+func TestFileJSONSchema(t *testing.T) {
+	var schema = File{}.JSONSchema()
+	assert.Len(t, schema.OneOf, 2)
+	assert.Equal(t, "string", schema.OneOf[0].Type)
+	assert.Equal(t, "object", schema.OneOf[1].Type)
+	assert.Contains(t, schema.OneOf[1].Properties, "src")
+}
@@ -19,30 +19,36 @@ import (
 // GitInfo includes tags and diffs used in some point
 type GitInfo struct {
 	CurrentTag  string
+	PreviousTag string
 	Commit      string
 	ShortCommit string
 	FullCommit  string
+	CommitDate  time.Time
 	URL         string
 }
 
 // Context carries along some data through the pipes
 type Context struct {
 	ctx.Context
-	Config       config.Project
-	Env          map[string]string
-	Token        string
-	Git          GitInfo
-	Artifacts    artifact.Artifacts
-	ReleaseNotes string
-	Version      string
-	Snapshot     bool
-	SkipPublish  bool
-	SkipSign     bool
-	SkipValidate bool
-	RmDist       bool
-	Debug        bool
-	PreRelease   bool
-	Parallelism  int
+	Config         config.Project
+	Env            map[string]string
+	Token          string
+	ChangelogToken string
+	Git            GitInfo
+	Artifacts      artifact.Artifacts
+	ReleaseNotes   string
+	ReleaseHeader  string
+	ReleaseFooter  string
+	Version        string
+	Snapshot       bool
+	SkipPublish    bool
+	SkipSign       bool
+	SkipValidate   bool
+	RmDist         bool
+	Strict         bool
+	Debug          bool
+	PreRelease     bool
+	Parallelism    int
 }
 
 // New context
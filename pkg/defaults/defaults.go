@@ -9,17 +9,20 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/artifactory"
 	"github.com/goreleaser/goreleaser/internal/pipe/brew"
 	"github.com/goreleaser/goreleaser/internal/pipe/build"
+	"github.com/goreleaser/goreleaser/internal/pipe/changelog"
 	"github.com/goreleaser/goreleaser/internal/pipe/checksums"
 	"github.com/goreleaser/goreleaser/internal/pipe/docker"
 	"github.com/goreleaser/goreleaser/internal/pipe/env"
 	"github.com/goreleaser/goreleaser/internal/pipe/nfpm"
 	"github.com/goreleaser/goreleaser/internal/pipe/project"
+	"github.com/goreleaser/goreleaser/internal/pipe/put"
 	"github.com/goreleaser/goreleaser/internal/pipe/release"
 	"github.com/goreleaser/goreleaser/internal/pipe/s3"
 	"github.com/goreleaser/goreleaser/internal/pipe/scoop"
 	"github.com/goreleaser/goreleaser/internal/pipe/sign"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapcraft"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapshot"
+	"github.com/goreleaser/goreleaser/internal/pipe/source"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -38,8 +41,10 @@ var Defaulters = []Defaulter{
 	env.Pipe{},
 	snapshot.Pipe{},
 	release.Pipe{},
+	changelog.Pipe{},
 	project.Pipe{},
 	archive.Pipe{},
+	source.Pipe{},
 	build.Pipe{},
 	nfpm.Pipe{},
 	snapcraft.Pipe{},
@@ -47,6 +52,7 @@ var Defaulters = []Defaulter{
 	sign.Pipe{},
 	docker.Pipe{},
 	artifactory.Pipe{},
+	put.Pipe{},
 	s3.Pipe{},
 	brew.Pipe{},
 	scoop.Pipe{},
@@ -0,0 +1,189 @@
+// Package jsonschema generates JSON Schema documents from Go types.
+//
+// Field names follow the same rules used by gopkg.in/yaml.v2, so the output
+// can be used to validate and autocomplete YAML files that are unmarshaled
+// into those types.
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is the JSON Schema draft the generated documents conform to
+const Version = "http://json-schema.org/draft-07/schema#"
+
+// Schema represents a JSON Schema document or sub-document
+type Schema struct {
+	Version              string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// Schemer can be implemented by types that have a custom YAML representation
+// and thus need to describe their own schema.
+type Schemer interface {
+	JSONSchema() *Schema
+}
+
+// nolint: gochecknoglobals
+var schemerType = reflect.TypeOf((*Schemer)(nil)).Elem()
+
+// Reflect generates the schema of the given value, which must be a struct or
+// a pointer to a struct.
+func Reflect(v interface{}) *Schema {
+	var r = reflector{definitions: map[string]*Schema{}}
+	var schema = r.reflect(reflect.TypeOf(v))
+	schema.Version = Version
+	schema.Definitions = r.definitions
+	return schema
+}
+
+type reflector struct {
+	definitions map[string]*Schema
+}
+
+func (r reflector) reflect(t reflect.Type) *Schema {
+	if t.Implements(schemerType) {
+		return reflect.Zero(t).Interface().(Schemer).JSONSchema()
+	}
+	if reflect.PtrTo(t).Implements(schemerType) {
+		return reflect.New(t).Interface().(Schemer).JSONSchema()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return r.reflect(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.reflect(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem())}
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	}
+	return &Schema{}
+}
+
+func (r reflector) reflectStruct(t reflect.Type) *Schema {
+	var ref = &Schema{Ref: "#/definitions/" + t.Name()}
+	if _, ok := r.definitions[t.Name()]; ok {
+		return ref
+	}
+	var schema = &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	// registered before walking the fields so recursive types terminate
+	r.definitions[t.Name()] = schema
+	r.addFields(schema, t)
+	return ref
+}
+
+func (r reflector) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name, inline := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			r.addFields(schema, field.Type)
+			continue
+		}
+		var prop = r.reflect(field.Type)
+		applyTag(prop, field)
+		schema.Properties[name] = prop
+	}
+}
+
+// yamlName returns the key gopkg.in/yaml.v2 uses for the given field and
+// whether its fields are inlined in the parent.
+func yamlName(field reflect.StructField) (string, bool) {
+	var parts = strings.Split(field.Tag.Get("yaml"), ",")
+	for _, flag := range parts[1:] {
+		if flag == "inline" {
+			return "", true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], false
+	}
+	return strings.ToLower(field.Name), false
+}
+
+// applyTag parses the jsonschema struct tag, which is a comma separated list
+// of enum, default and type entries, e.g. `jsonschema:"enum=zip,default=zip"`.
+func applyTag(schema *Schema, field reflect.StructField) {
+	var tag = field.Tag.Get("jsonschema")
+	if tag == "" {
+		return
+	}
+	var enum = schema
+	if schema.Items != nil {
+		// enums of lists constrain their items
+		enum = schema.Items
+	}
+	var defaults, types []string
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "enum":
+			enum.Enum = append(enum.Enum, kv[1])
+		case "default":
+			defaults = append(defaults, kv[1])
+		case "type":
+			types = append(types, kv[1])
+		}
+	}
+	if len(types) > 0 {
+		// YAML scalars may resolve to more than one Go type
+		schema.Type = ""
+		for _, t := range types {
+			schema.OneOf = append(schema.OneOf, &Schema{Type: t})
+		}
+	}
+	if len(defaults) > 0 {
+		schema.Default = defaultValue(field.Type, defaults)
+	}
+}
+
+func defaultValue(t reflect.Type, values []string) interface{} {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var result = make([]interface{}, 0, len(values))
+		for _, v := range values {
+			result = append(result, v)
+		}
+		return result
+	case reflect.Bool:
+		b, _ := strconv.ParseBool(values[0])
+		return b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _ := strconv.Atoi(values[0])
+		return i
+	}
+	return values[0]
+}
@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type union []string
+
+func (union) JSONSchema() *Schema {
+	return &Schema{OneOf: []*Schema{{Type: "string"}, {Type: "array"}}}
+}
+
+type inlined struct {
+	Inner string `yaml:"inner,omitempty"`
+}
+
+type child struct {
+	Name string
+	Kids []child `yaml:",omitempty"`
+}
+
+type root struct {
+	inlined       `yaml:",inline"`
+	Format        string            `yaml:",omitempty" jsonschema:"enum=zip,enum=tar.gz,default=tar.gz"`
+	NameTemplate  string            `yaml:"name_template,omitempty"`
+	Skip          string            `yaml:"-"`
+	Formats       []string          `yaml:",omitempty" jsonschema:"enum=deb,enum=rpm,default=deb"`
+	Enabled       bool              `jsonschema:"default=true"`
+	Count         int               `jsonschema:"default=4"`
+	Wrap          string            `jsonschema:"type=string,type=boolean"`
+	Replacements  map[string]string `yaml:",omitempty"`
+	Flags         union             `yaml:",omitempty"`
+	Child         *child            `yaml:",omitempty"`
+	Children      []child           `yaml:",omitempty"`
+	unexported    string
+	UnknownTagKey string `jsonschema:"whatever"`
+}
+
+func TestReflect(t *testing.T) {
+	var schema = Reflect(&root{})
+	assert.Equal(t, Version, schema.Version)
+	assert.Equal(t, "#/definitions/root", schema.Ref)
+	require.Len(t, schema.Definitions, 2)
+
+	var def = schema.Definitions["root"]
+	require.NotNil(t, def)
+	assert.Equal(t, "object", def.Type)
+	assert.Equal(t, false, def.AdditionalProperties)
+	assert.Len(t, def.Properties, 12)
+	assert.NotContains(t, def.Properties, "skip")
+	assert.NotContains(t, def.Properties, "unexported")
+
+	assert.Equal(t, &Schema{Type: "string"}, def.Properties["inner"])
+	assert.Equal(t, &Schema{Type: "string"}, def.Properties["name_template"])
+	assert.Equal(t, &Schema{Type: "string"}, def.Properties["unknowntagkey"])
+	assert.Equal(t, &Schema{
+		Type:    "string",
+		Enum:    []interface{}{"zip", "tar.gz"},
+		Default: "tar.gz",
+	}, def.Properties["format"])
+	assert.Equal(t, &Schema{
+		Type:    "array",
+		Items:   &Schema{Type: "string", Enum: []interface{}{"deb", "rpm"}},
+		Default: []interface{}{"deb"},
+	}, def.Properties["formats"])
+	assert.Equal(t, &Schema{Type: "boolean", Default: true}, def.Properties["enabled"])
+	assert.Equal(t, &Schema{Type: "integer", Default: 4}, def.Properties["count"])
+	assert.Equal(t, &Schema{
+		OneOf: []*Schema{{Type: "string"}, {Type: "boolean"}},
+	}, def.Properties["wrap"])
+	assert.Equal(t, &Schema{
+		Type:                 "object",
+		AdditionalProperties: &Schema{Type: "string"},
+	}, def.Properties["replacements"])
+	assert.Equal(t, union{}.JSONSchema(), def.Properties["flags"])
+	assert.Equal(t, &Schema{Ref: "#/definitions/child"}, def.Properties["child"])
+	assert.Equal(t, &Schema{
+		Type:  "array",
+		Items: &Schema{Ref: "#/definitions/child"},
+	}, def.Properties["children"])
+
+	var kid = schema.Definitions["child"]
+	require.NotNil(t, kid)
+	assert.Equal(t, &Schema{Type: "string"}, kid.Properties["name"])
+	assert.Equal(t, &Schema{
+		Type:  "array",
+		Items: &Schema{Ref: "#/definitions/child"},
+	}, kid.Properties["kids"])
+}